@@ -0,0 +1,129 @@
+package iri
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+
+Builder constructs deep IRIs reusing internal buffer, segments are copied
+only when IRI is built. It is useful for depth-first traversal of trees.
+
+  b := iri.NewBuilder(base)
+  b.Push("a").Push("b").Build() ⟼ base:a:b
+  b.Pop().Push("c").Build()     ⟼ base:a:c
+*/
+type Builder struct {
+	seq []string
+}
+
+/*
+
+NewBuilder creates a builder starting from the base IRI
+*/
+func NewBuilder(base ID) *Builder {
+	seq := base.IRI.seq()
+	return &Builder{seq: append(make([]string, 0, len(seq)+8), seq...)}
+}
+
+/*
+
+Push appends segment
+*/
+func (b *Builder) Push(segment string) *Builder {
+	b.seq = append(b.seq, segment)
+	return b
+}
+
+/*
+
+Pop removes the last segment, it is no-op for the empty builder
+*/
+func (b *Builder) Pop() *Builder {
+	if len(b.seq) > 0 {
+		b.seq = b.seq[:len(b.seq)-1]
+	}
+	return b
+}
+
+/*
+
+Build returns the assembled identity
+*/
+func (b *Builder) Build() ID {
+	if len(b.seq) == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}
+	}
+
+	return ID{IRI: IRI{Seq: append([]string{}, b.seq...)}}
+}
+
+/*
+
+SafeBuilder assembles IRI segment by segment, validating each one.
+Invalid segment is rejected at the moment it is appended, the builder
+remains usable afterwards. It is the strict counterpart of Builder.
+
+  b := iri.NewSafeBuilder(nil)
+  if err := b.Append("a"); err != nil { ... }
+  id := b.Build()
+*/
+type SafeBuilder struct {
+	valid func(string) bool
+	seq   []string
+}
+
+/*
+
+NewSafeBuilder creates a builder, the optional predicate is applied to each
+segment in addition to the built-in rules (non-blank, no separator).
+*/
+func NewSafeBuilder(valid func(segment string) bool) *SafeBuilder {
+	return &SafeBuilder{valid: valid}
+}
+
+/*
+
+Append adds segment to IRI or returns error if segment is invalid
+*/
+func (b *SafeBuilder) Append(segment string) error {
+	pos := len(b.seq)
+
+	if err := validSegment(pos, segment); err != nil {
+		return err
+	}
+
+	if b.valid != nil && !b.valid(segment) {
+		return fmt.Errorf("iri: segment %d %q is rejected by predicate", pos, segment)
+	}
+
+	b.seq = append(b.seq, segment)
+	return nil
+}
+
+/*
+
+Build returns the assembled identity
+*/
+func (b *SafeBuilder) Build() ID {
+	if len(b.seq) == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}
+	}
+
+	return ID{IRI: IRI{Seq: append([]string{}, b.seq...)}}
+}
+
+// validSegment checks segment at position against common rules
+func validSegment(pos int, segment string) error {
+	switch {
+	case segment == "":
+		return fmt.Errorf("iri: segment %d is empty", pos)
+	case strings.TrimSpace(segment) == "":
+		return fmt.Errorf("iri: segment %d is blank", pos)
+	case strings.Contains(segment, ":"):
+		return fmt.Errorf("iri: segment %d %q contains separator", pos, segment)
+	}
+
+	return nil
+}
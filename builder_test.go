@@ -0,0 +1,93 @@
+package iri_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestBuilder(t *testing.T) {
+	b := iri.NewBuilder(r0)
+
+	it.Ok(t).
+		If(b.Build()).Should().Equal(r0).
+		If(b.Push("a").Build()).Should().Equal(r1).
+		If(b.Push("b").Push("c").Build()).Should().Equal(r3).
+		If(b.Pop().Push("t").Build()).Should().Equal(r2.Heir("t")).
+		If(b.Pop().Pop().Pop().Pop().Build()).Should().Equal(r0)
+}
+
+func TestBuilderBase(t *testing.T) {
+	b := iri.NewBuilder(r2)
+
+	it.Ok(t).
+		If(b.Build()).Should().Equal(r2).
+		If(b.Push("c").Push("d").Build()).Should().Equal(r2.Heir("c").Heir("d")).
+		If(r2).Should().Equal(iri.New("a:b"))
+}
+
+func TestBuilderImmutable(t *testing.T) {
+	b := iri.NewBuilder(r0)
+	id := b.Push("a").Push("b").Build()
+	b.Pop().Push("x")
+
+	it.Ok(t).
+		If(id).Should().Equal(r2).
+		If(b.Build()).Should().Equal(iri.New("a:x"))
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	builder := iri.NewBuilder(r2)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		builder.Push("c").Push("d")
+		_ = builder.Build()
+		builder.Pop().Pop()
+	}
+}
+
+func BenchmarkBuilderHeir(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		id := r2.Heir("c").Heir("d")
+		_ = id.Parent().Parent()
+	}
+}
+
+func TestSafeBuilder(t *testing.T) {
+	b := iri.NewSafeBuilder(nil)
+
+	it.Ok(t).
+		If(b.Build()).Should().Equal(r0).
+		If(b.Append("a")).Should().Equal(nil).
+		If(b.Append("b")).Should().Equal(nil).
+		If(b.Append("c")).Should().Equal(nil).
+		If(b.Build()).Should().Equal(r3)
+}
+
+func TestSafeBuilderReject(t *testing.T) {
+	b := iri.NewSafeBuilder(func(s string) bool { return strings.ToLower(s) == s })
+
+	it.Ok(t).
+		If(b.Append("a")).Should().Equal(nil).
+		If(b.Append("")).ShouldNot().Equal(nil).
+		If(b.Append("x:y")).ShouldNot().Equal(nil).
+		If(b.Append("B")).ShouldNot().Equal(nil).
+		If(b.Build()).Should().Equal(r1).
+		If(b.Append("b")).Should().Equal(nil).
+		If(b.Build()).Should().Equal(r2)
+}
+
+func TestSafeBuilderImmutable(t *testing.T) {
+	b := iri.NewSafeBuilder(nil)
+	b.Append("a")
+	id := b.Build()
+	b.Append("b")
+
+	it.Ok(t).
+		If(id).Should().Equal(r1).
+		If(b.Build()).Should().Equal(r2)
+}
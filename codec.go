@@ -0,0 +1,223 @@
+package iri
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/*
+
+Segmented is an identity encoded to JSON as array of segments
+
+  type MyStruct struct {
+		ID iri.Segmented `json:"id"`
+	}
+*/
+type Segmented struct {
+	ID
+}
+
+/*
+
+MarshalJSON `IRI ⟼ ["prefix", "suffix"]`
+*/
+func (iri Segmented) MarshalJSON() ([]byte, error) {
+	seq := iri.IRI.seq()
+	if seq == nil {
+		seq = []string{}
+	}
+
+	return json.Marshal(seq)
+}
+
+/*
+
+UnmarshalJSON `["prefix", "suffix"] ⟼ IRI`, the compact "prefix:suffix" is accepted
+*/
+func (iri *Segmented) UnmarshalJSON(b []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		return iri.ID.IRI.UnmarshalJSON(b)
+	}
+
+	var seq []string
+	if err := json.Unmarshal(b, &seq); err != nil {
+		return err
+	}
+
+	if err := checkDepth(len(seq)); err != nil {
+		return err
+	}
+
+	*iri = Segmented{ID: Join(seq...)}
+	return nil
+}
+
+/*
+
+PathID is an identity encoded to JSON as path "prefix/suffix"
+
+  type MyStruct struct {
+		ID iri.PathID `json:"id"`
+	}
+*/
+type PathID struct {
+	ID
+}
+
+/*
+
+MarshalJSON `IRI ⟼ "prefix/suffix"`
+*/
+func (iri PathID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(iri.IRI.seq(), "/"))
+}
+
+/*
+
+UnmarshalJSON `"prefix/suffix" ⟼ IRI`, leading and trailing "/" are ignored
+*/
+func (iri *PathID) UnmarshalJSON(b []byte) error {
+	var path string
+	if err := json.Unmarshal(b, &path); err != nil {
+		return err
+	}
+
+	if err := checkDepth(strings.Count(path, "/") + 1); err != nil {
+		return err
+	}
+
+	*iri = PathID{ID: ID{IRI: splitPath(path)}}
+	return nil
+}
+
+/*
+
+Tree converts IRI to nested map, each segment is a key of single-child
+object, the leaf is an empty object
+
+  iri.New("a:b:c").Tree() ⟼ {"a": {"b": {"c": {}}}}
+*/
+func (iri ID) Tree() map[string]interface{} {
+	tree := map[string]interface{}{}
+	seq := iri.IRI.seq()
+	for i := len(seq) - 1; i >= 0; i-- {
+		tree = map[string]interface{}{seq[i]: tree}
+	}
+
+	return tree
+}
+
+/*
+
+FromTree builds IRI from nested map by following the single-child chain,
+it fails if the tree branches. The empty object is the empty IRI.
+
+  iri.FromTree({"a": {"b": {"c": {}}}}) ⟼ a:b:c
+*/
+func FromTree(tree map[string]interface{}) (ID, error) {
+	seq := []string{}
+	for len(tree) != 0 {
+		if len(tree) > 1 {
+			return ID{}, fmt.Errorf("iri: tree branches at segment %d", len(seq))
+		}
+
+		for key, val := range tree {
+			node, ok := val.(map[string]interface{})
+			if !ok {
+				return ID{}, fmt.Errorf("iri: tree node %q is %T, object is expected", key, val)
+			}
+			seq = append(seq, key)
+			tree = node
+		}
+	}
+
+	return Join(seq...), nil
+}
+
+/*
+
+Proto converts IRI to string field of protobuf message, it is the compact
+form "prefix:suffix" escaped same way as String and other serializers.
+*/
+func (iri ID) Proto() string {
+	return iri.IRI.String()
+}
+
+/*
+
+FromProto builds IRI from string field of protobuf message, it decodes
+the compact form produced by Proto, FromProto(x.Proto()) equals to x.
+*/
+func FromProto(s string) ID {
+	return ID{IRI: parse(s)}
+}
+
+/*
+
+MarshalBinaryKey encodes IRI to bytes, which are ordered same way as
+Compare orders IRIs, suitable for keys of ordered key-value stores.
+The compact string is not suitable, "a:b" is ordered after "a-b" but
+segment "a" is before "a-b". Each segment is terminated by 0x00 0x01,
+0x00 within segment is escaped as 0x00 0xFF. The empty IRI has empty key.
+
+  iri.New("a:b").MarshalBinaryKey() ⟼ 61 00 01 62 00 01
+*/
+func (iri ID) MarshalBinaryKey() []byte {
+	seq := iri.IRI.seq()
+	size := 0
+	for _, x := range seq {
+		size += len(x) + 2
+	}
+
+	key := make([]byte, 0, size)
+	for _, x := range seq {
+		for i := 0; i < len(x); i++ {
+			if x[i] == 0x00 {
+				key = append(key, 0x00, 0xff)
+				continue
+			}
+			key = append(key, x[i])
+		}
+		key = append(key, 0x00, 0x01)
+	}
+
+	return key
+}
+
+/*
+
+UnmarshalBinaryKey decodes IRI from bytes produced by MarshalBinaryKey
+*/
+func UnmarshalBinaryKey(key []byte) (ID, error) {
+	seq := []string{}
+	seg := make([]byte, 0, len(key))
+
+	for i := 0; i < len(key); i++ {
+		if key[i] != 0x00 {
+			seg = append(seg, key[i])
+			continue
+		}
+
+		if i+1 == len(key) {
+			return ID{}, fmt.Errorf("iri: malformed binary key, unexpected end at %d", i)
+		}
+
+		switch i++; key[i] {
+		case 0x01:
+			seq = append(seq, string(seg))
+			seg = seg[:0]
+		case 0xff:
+			seg = append(seg, 0x00)
+		default:
+			return ID{}, fmt.Errorf("iri: malformed binary key, invalid escape 0x%02x at %d", key[i], i)
+		}
+	}
+
+	if len(seg) != 0 {
+		return ID{}, fmt.Errorf("iri: malformed binary key, segment is not terminated")
+	}
+
+	return Join(seq...), nil
+}
@@ -0,0 +1,236 @@
+package iri_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestSegmented(t *testing.T) {
+	type Struct struct {
+		ID iri.Segmented `json:"id"`
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.Segmented{ID: iri.New("")}}:      "{\"id\":[]}",
+		{ID: iri.Segmented{ID: iri.New("a")}}:     "{\"id\":[\"a\"]}",
+		{ID: iri.Segmented{ID: iri.New("a:b:c")}}: "{\"id\":[\"a\",\"b\",\"c\"]}",
+		{ID: iri.Segmented{ID: iri.Join("a:b")}}:  "{\"id\":[\"a:b\"]}",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := json.Marshal(eg)
+		err2 := json.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(*eg).Should().Equal(in).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestSegmentedCompact(t *testing.T) {
+	type Struct struct {
+		ID iri.Segmented `json:"id"`
+	}
+
+	test := map[string]iri.ID{
+		"{\"id\":\"\"}":      r0,
+		"{\"id\":\"a\"}":     r1,
+		"{\"id\":\"a:b:c\"}": r3,
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+		err := json.Unmarshal([]byte(eg), &in)
+
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(in.ID.ID).Should().Equal(expect)
+	}
+}
+
+func TestPathID(t *testing.T) {
+	type Struct struct {
+		ID iri.PathID `json:"id"`
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.PathID{ID: iri.New("")}}:      "{\"id\":\"\"}",
+		{ID: iri.PathID{ID: iri.New("a")}}:     "{\"id\":\"a\"}",
+		{ID: iri.PathID{ID: iri.New("a:b:c")}}: "{\"id\":\"a/b/c\"}",
+		{ID: iri.PathID{ID: iri.Join("a:b")}}:  "{\"id\":\"a:b\"}",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := json.Marshal(eg)
+		err2 := json.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(*eg).Should().Equal(in).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestPathIDSlash(t *testing.T) {
+	type Struct struct {
+		ID iri.PathID `json:"id"`
+	}
+
+	test := map[string]iri.ID{
+		"{\"id\":\"/\"}":      r0,
+		"{\"id\":\"/a/b/c\"}": r3,
+		"{\"id\":\"a/b/\"}":   r2,
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+		err := json.Unmarshal([]byte(eg), &in)
+
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(in.ID.ID).Should().Equal(expect)
+	}
+}
+
+func TestTree(t *testing.T) {
+	for _, id := range []iri.ID{r0, r1, r3, iri.Join("a", "b:c")} {
+		tree, err1 := json.Marshal(id.Tree())
+
+		var m map[string]interface{}
+		err2 := json.Unmarshal(tree, &m)
+		back, err3 := iri.FromTree(m)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(err3).Should().Equal(nil).
+			If(back).Should().Equal(id)
+	}
+
+	tree, _ := json.Marshal(r3.Tree())
+	it.Ok(t).
+		If(string(tree)).Should().Equal("{\"a\":{\"b\":{\"c\":{}}}}")
+}
+
+func TestFromTreeBranch(t *testing.T) {
+	test := []map[string]interface{}{
+		{"a": map[string]interface{}{"b": map[string]interface{}{}, "c": map[string]interface{}{}}},
+		{"a": map[string]interface{}{}, "b": map[string]interface{}{}},
+		{"a": "b"},
+	}
+
+	for _, eg := range test {
+		_, err := iri.FromTree(eg)
+		it.Ok(t).If(err).ShouldNot().Equal(nil)
+	}
+}
+
+func TestProto(t *testing.T) {
+	test := []iri.ID{
+		r0, r1, r5,
+		iri.Join("a", "12:00", "b"),
+		iri.Join("a", "100%", "%3A"),
+		iri.New("a::b"),
+	}
+
+	for _, id := range test {
+		it.Ok(t).
+			If(iri.FromProto(id.Proto())).Should().Equal(id).
+			If(id.Proto()).Should().Equal(id.String())
+	}
+
+	it.Ok(t).
+		If(iri.Join("a", "12:00").Proto()).Should().Equal("a:12%3A00").
+		If(iri.FromProto("a:12%3A00")).Should().Equal(iri.Join("a", "12:00"))
+}
+
+func TestSegmentedMaxSegments(t *testing.T) {
+	defer func(n int) { iri.MaxSegments = n }(iri.MaxSegments)
+	iri.MaxSegments = 2
+
+	var id iri.Segmented
+	it.Ok(t).
+		If(json.Unmarshal([]byte("[\"a\",\"b\"]"), &id)).Should().Equal(nil).
+		If(json.Unmarshal([]byte("[\"a\",\"b\",\"c\"]"), &id)).ShouldNot().Equal(nil).
+		If(json.Unmarshal([]byte("\"a:b:c\""), &id)).ShouldNot().Equal(nil)
+}
+
+func TestBinaryKey(t *testing.T) {
+	test := []iri.ID{
+		r0, r1, r2, r3, r5,
+		iri.New("a-b"),
+		iri.New("a:"),
+		iri.New("a::b"),
+		iri.Join("a", "b:c"),
+		iri.Join("a\x00", "b"),
+		iri.Join("a", "\x00\x01"),
+		iri.Join("\xff", "b"),
+		iri.New("ab"),
+		iri.New("b"),
+	}
+
+	for _, id := range test {
+		back, err := iri.UnmarshalBinaryKey(id.MarshalBinaryKey())
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(back).Should().Equal(id)
+	}
+
+	for _, a := range test {
+		for _, b := range test {
+			it.Ok(t).
+				If(bytes.Compare(a.MarshalBinaryKey(), b.MarshalBinaryKey())).Should().Equal(a.Compare(b))
+		}
+	}
+
+	it.Ok(t).
+		If(r2.MarshalBinaryKey()).Should().Equal([]byte{'a', 0x00, 0x01, 'b', 0x00, 0x01}).
+		If(len(r0.MarshalBinaryKey())).Should().Equal(0)
+}
+
+func TestBinaryKeySort(t *testing.T) {
+	ids := []iri.ID{r3, iri.New("a-b"), r2, iri.New("a:b-c"), r1, iri.New("a:b:c:d")}
+
+	keys := make([][]byte, len(ids))
+	for i, id := range ids {
+		keys[i] = id.MarshalBinaryKey()
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Compare(ids[j]) < 0 })
+
+	for i, key := range keys {
+		id, err := iri.UnmarshalBinaryKey(key)
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(id).Should().Equal(ids[i])
+	}
+
+	it.Ok(t).
+		If(ids[0]).Should().Equal(r1).
+		If(ids[1]).Should().Equal(r2).
+		If(ids[2]).Should().Equal(r3)
+}
+
+func TestBinaryKeyMalformed(t *testing.T) {
+	for _, key := range [][]byte{
+		{'a'},
+		{'a', 0x00},
+		{'a', 0x00, 0x02},
+		{'a', 0x00, 0x01, 'b'},
+	} {
+		_, err := iri.UnmarshalBinaryKey(key)
+		it.Ok(t).If(err).ShouldNot().Equal(nil)
+	}
+}
@@ -0,0 +1,62 @@
+package iri
+
+/*
+
+MinimalCover returns the minimal set of prefixes that covers same IRIs as
+input. A prefix is dropped if it is a descendant of (or equal to) another
+prefix in the set. The order of remaining prefixes is preserved.
+*/
+func MinimalCover(prefixes []ID) []ID {
+	cover := make([]ID, 0, len(prefixes))
+
+	for i, x := range prefixes {
+		covered := false
+		for j, y := range prefixes {
+			if i == j || !x.HasPrefix(y) {
+				continue
+			}
+
+			// equal prefixes are covered by the first one
+			if len(y.IRI.seq()) < len(x.IRI.seq()) || j < i {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			cover = append(cover, x)
+		}
+	}
+
+	return cover
+}
+
+/*
+
+Overlaps returns true if either IRI is prefix of the other one, equal IRIs
+overlap. Unlike CommonPrefix, it answers containment question.
+
+  iri.Overlaps(iri.New("a:b"), iri.New("a:b:c")) ⟼ true
+  iri.Overlaps(iri.New("a:b"), iri.New("a:c"))   ⟼ false
+*/
+func Overlaps(a, b ID) bool {
+	return a.HasPrefix(b) || b.HasPrefix(a)
+}
+
+/*
+
+FindOverlaps returns index pairs {i, j} of overlapping IRIs, where i < j.
+Pairs are ordered by i then by j.
+*/
+func FindOverlaps(ids []ID) [][2]int {
+	pairs := [][2]int{}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			if Overlaps(ids[i], ids[j]) {
+				pairs = append(pairs, [2]int{i, j})
+			}
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,51 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestMinimalCoverNested(t *testing.T) {
+	it.Ok(t).
+		If(iri.MinimalCover([]iri.ID{r3, r2, r5, r4})).Should().Equal([]iri.ID{r2}).
+		If(iri.MinimalCover([]iri.ID{r2, r2, r3})).Should().Equal([]iri.ID{r2}).
+		If(iri.MinimalCover([]iri.ID{r3, r0, r1})).Should().Equal([]iri.ID{r0})
+}
+
+func TestMinimalCoverDisjoint(t *testing.T) {
+	x := iri.New("x:y")
+	z := iri.New("a:bc")
+
+	it.Ok(t).
+		If(iri.MinimalCover([]iri.ID{r2, x, z})).Should().Equal([]iri.ID{r2, x, z}).
+		If(iri.MinimalCover([]iri.ID{r3, x, r2, x.Heir("z")})).Should().Equal([]iri.ID{x, r2}).
+		If(iri.MinimalCover([]iri.ID{})).Should().Equal([]iri.ID{})
+}
+
+func TestOverlaps(t *testing.T) {
+	it.Ok(t).
+		If(iri.Overlaps(r2, r4)).Should().Equal(true).
+		If(iri.Overlaps(r4, r2)).Should().Equal(true).
+		If(iri.Overlaps(r3, r3)).Should().Equal(true).
+		If(iri.Overlaps(r0, r3)).Should().Equal(true).
+		If(iri.Overlaps(r2, iri.New("a:c"))).Should().Equal(false).
+		If(iri.Overlaps(iri.New("a:bc"), r2)).Should().Equal(false).
+		If(iri.Overlaps(iri.New("x"), r1)).Should().Equal(false)
+}
+
+func TestFindOverlaps(t *testing.T) {
+	ids := []iri.ID{
+		iri.New("a:b"),
+		iri.New("x:y"),
+		iri.New("a:b:c"),
+		iri.New("a:c"),
+		iri.New("x:y"),
+	}
+
+	it.Ok(t).
+		If(iri.FindOverlaps(ids)).Should().Equal([][2]int{{0, 2}, {1, 4}}).
+		If(iri.FindOverlaps([]iri.ID{r1, iri.New("b")})).Should().Equal([][2]int{}).
+		If(iri.FindOverlaps(nil)).Should().Equal([][2]int{})
+}
@@ -0,0 +1,59 @@
+package iri
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+
+Namespaces maps CURIE prefixes to namespace URLs
+
+  ns := iri.Namespaces{"foaf": "http://xmlns.com/foaf/0.1/"}
+  ns.Expand(iri.New("foaf:name")) ⟼ "http://xmlns.com/foaf/0.1/name"
+*/
+type Namespaces map[string]string
+
+/*
+
+Expand converts compact IRI to full URL. The first segment is a prefix of
+namespace, the remaining segments are the local part.
+*/
+func (ns Namespaces) Expand(id ID) (string, error) {
+	seq := id.IRI.seq()
+	if len(seq) == 0 {
+		return "", fmt.Errorf("iri: empty IRI cannot be expanded")
+	}
+
+	url, exists := ns[seq[0]]
+	if !exists {
+		return "", fmt.Errorf("iri: unknown prefix %q", seq[0])
+	}
+
+	return url + strings.Join(seq[1:], ":"), nil
+}
+
+/*
+
+Compact converts full URL to compact IRI using the longest matching namespace.
+It returns false if none of namespaces matches.
+*/
+func (ns Namespaces) Compact(url string) (ID, bool) {
+	prefix, base := "", ""
+	for p, u := range ns {
+		if strings.HasPrefix(url, u) && (len(u) > len(base) || (len(u) == len(base) && p < prefix)) {
+			prefix, base = p, u
+		}
+	}
+
+	if base == "" {
+		return ID{}, false
+	}
+
+	local := url[len(base):]
+	if local == "" {
+		return Join(prefix), true
+	}
+
+	return Join(append([]string{prefix}, strings.Split(local, ":")...)...), true
+}
@@ -0,0 +1,60 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+var ns = iri.Namespaces{
+	"foaf":   "http://xmlns.com/foaf/0.1/",
+	"rdf":    "http://www.w3.org/1999/02/22-rdf-syntax-ns#",
+	"schema": "https://schema.org/",
+	"person": "https://schema.org/Person/",
+}
+
+func TestNamespacesExpand(t *testing.T) {
+	test := map[string]string{
+		"foaf:name":     "http://xmlns.com/foaf/0.1/name",
+		"rdf:type":      "http://www.w3.org/1999/02/22-rdf-syntax-ns#type",
+		"schema:Person": "https://schema.org/Person",
+		"person:name":   "https://schema.org/Person/name",
+		"foaf:a:b":      "http://xmlns.com/foaf/0.1/a:b",
+	}
+
+	for eg, expect := range test {
+		url, err := ns.Expand(iri.New("%s", eg))
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(url).Should().Equal(expect)
+	}
+}
+
+func TestNamespacesExpandUnknown(t *testing.T) {
+	_, err1 := ns.Expand(iri.New("dc:title"))
+	_, err2 := ns.Expand(iri.New(""))
+
+	it.Ok(t).
+		If(err1).ShouldNot().Equal(nil).
+		If(err2).ShouldNot().Equal(nil)
+}
+
+func TestNamespacesCompact(t *testing.T) {
+	test := []string{"foaf:name", "rdf:type", "schema:Person", "person:name", "foaf:a:b"}
+
+	for _, eg := range test {
+		url, _ := ns.Expand(iri.New("%s", eg))
+		id, ok := ns.Compact(url)
+		it.Ok(t).
+			If(ok).Should().Equal(true).
+			If(id).Should().Equal(iri.New("%s", eg))
+	}
+}
+
+func TestNamespacesCompactUnknown(t *testing.T) {
+	_, ok := ns.Compact("http://purl.org/dc/elements/1.1/title")
+
+	it.Ok(t).
+		If(ok).Should().Equal(false)
+}
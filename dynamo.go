@@ -0,0 +1,204 @@
+package iri
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+/*
+
+SortKeyID is an identity stored as hierarchical key pair. Unlike ID,
+which is stored as flat string "prefix:suffix", the attribute is a map
+of parent IRI (pk) and local name (sk), children of IRI are queried by pk.
+The pair is ID.PartitionKey and ID.SortKey, the single segment IRI is
+stored as partition key with empty sort key.
+
+  type MyStruct struct {
+		Key iri.SortKeyID `dynamodbav:"key"`
+	}
+*/
+type SortKeyID struct {
+	ID
+}
+
+/*
+
+MarshalDynamoDBAttributeValue `IRI ⟼ {pk: "prefix", sk: "suffix"}`
+*/
+func (iri SortKeyID) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if iri.IRI.IsEmpty() {
+		av.NULL = aws.Bool(true)
+		return nil
+	}
+
+	av.M = map[string]*dynamodb.AttributeValue{
+		"pk": {S: aws.String(iri.PartitionKey())},
+		"sk": {S: aws.String(iri.SortKey())},
+	}
+	return nil
+}
+
+/*
+
+UnmarshalDynamoDBAttributeValue `{pk: "prefix", sk: "suffix"} ⟼ IRI`
+*/
+func (iri *SortKeyID) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if av.M == nil {
+		*iri = SortKeyID{ID: New("")}
+		return nil
+	}
+
+	val := stringValue(av.M["pk"])
+	if sk := stringValue(av.M["sk"]); sk != "" {
+		val = val + ":" + sk
+	}
+
+	if err := checkSegments(val); err != nil {
+		return err
+	}
+
+	*iri = SortKeyID{ID: ID{IRI: parse(val)}}
+	return nil
+}
+
+/*
+
+PrefixSetID is an identity stored as DynamoDB String Set of all its prefixes,
+e.g. a:b:c is stored as {"a", "a:b", "a:b:c"}. It allows `contains` filters
+over ancestors of IRI.
+
+  type MyStruct struct {
+		Key iri.PrefixSetID `dynamodbav:"key"`
+	}
+*/
+type PrefixSetID struct {
+	ID
+}
+
+/*
+
+MarshalDynamoDBAttributeValue `IRI ⟼ ["prefix", "prefix:suffix"]`
+*/
+func (iri PrefixSetID) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if iri.IRI.IsEmpty() {
+		av.NULL = aws.Bool(true)
+		return nil
+	}
+
+	lineage := iri.Lineage()
+	av.SS = make([]*string, len(lineage))
+	for i, x := range lineage {
+		av.SS[i] = aws.String(x.String())
+	}
+	return nil
+}
+
+/*
+
+UnmarshalDynamoDBAttributeValue `["prefix", "prefix:suffix"] ⟼ IRI`, the longest prefix is IRI
+*/
+func (iri *PrefixSetID) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	id := New("")
+	for _, x := range av.SS {
+		val := aws.StringValue(x)
+		if err := checkSegments(val); err != nil {
+			return err
+		}
+
+		if prefix := (ID{IRI: parse(val)}); prefix.Len() > id.Len() {
+			id = prefix
+		}
+	}
+
+	*iri = PrefixSetID{ID: id}
+	return nil
+}
+
+/*
+
+ListID is an identity stored as DynamoDB List of segments, e.g. a:b:c is
+stored as [{S: "a"}, {S: "b"}, {S: "c"}]. The empty IRI is stored as empty list.
+
+  type MyStruct struct {
+		Key iri.ListID `dynamodbav:"key"`
+	}
+*/
+type ListID struct {
+	ID
+}
+
+/*
+
+MarshalDynamoDBAttributeValue `IRI ⟼ ["prefix", "suffix"]`
+*/
+func (iri ListID) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	seq := iri.IRI.seq()
+	av.L = make([]*dynamodb.AttributeValue, len(seq))
+	for i, x := range seq {
+		av.L[i] = &dynamodb.AttributeValue{S: aws.String(x)}
+	}
+	return nil
+}
+
+/*
+
+UnmarshalDynamoDBAttributeValue `["prefix", "suffix"] ⟼ IRI`
+*/
+func (iri *ListID) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	if err := checkDepth(len(av.L)); err != nil {
+		return err
+	}
+
+	seq := make([]string, len(av.L))
+	for i, x := range av.L {
+		seq[i] = stringValue(x)
+	}
+
+	*iri = ListID{ID: ID{IRI: IRI{}.Heirs(seq...)}}
+	return nil
+}
+
+/*
+
+EmptyStringID is an identity stored as DynamoDB string, the empty IRI is
+stored as empty string {S: ""} instead of NULL. Both NULL and empty string
+are read as the empty IRI, so that existing items are compatible.
+
+  type MyStruct struct {
+		Key iri.EmptyStringID `dynamodbav:"key"`
+	}
+*/
+type EmptyStringID struct {
+	ID
+}
+
+/*
+
+MarshalDynamoDBAttributeValue `IRI ⟼ "prefix:suffix"`, the empty IRI is ""
+*/
+func (iri EmptyStringID) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	av.S = aws.String(iri.IRI.String())
+	return nil
+}
+
+/*
+
+UnmarshalDynamoDBAttributeValue `"prefix:suffix" ⟼ IRI`, NULL and "" are the empty IRI
+*/
+func (iri *EmptyStringID) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	val := aws.StringValue(av.S)
+	if err := checkSegments(val); err != nil {
+		return err
+	}
+
+	*iri = EmptyStringID{ID: ID{IRI: parse(val)}}
+	return nil
+}
+
+func stringValue(av *dynamodb.AttributeValue) string {
+	if av == nil {
+		return ""
+	}
+
+	return aws.StringValue(av.S)
+}
@@ -0,0 +1,238 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestSortKeyID(t *testing.T) {
+	type Struct struct {
+		Key   iri.SortKeyID `dynamodbav:"key"`
+		Title string        `dynamodbav:"title"`
+	}
+
+	test := []Struct{
+		{Key: iri.SortKeyID{ID: iri.New("")}, Title: "t"},
+		{Key: iri.SortKeyID{ID: iri.New("a")}, Title: "t"},
+		{Key: iri.SortKeyID{ID: iri.New("a:b")}, Title: "t"},
+		{Key: iri.SortKeyID{ID: iri.New("a:b:c")}, Title: "t"},
+		{Key: iri.SortKeyID{ID: iri.Join("a", "12:00")}, Title: "t"},
+	}
+
+	for _, eg := range test {
+		in := Struct{}
+
+		gen, err1 := dynamodbattribute.MarshalMap(eg)
+		err2 := dynamodbattribute.UnmarshalMap(gen, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(eg).Should().Equal(in)
+	}
+}
+
+func TestSortKeyIDSplit(t *testing.T) {
+	type Struct struct {
+		Key iri.SortKeyID `dynamodbav:"key"`
+	}
+
+	gen, err := dynamodbattribute.MarshalMap(Struct{Key: iri.SortKeyID{ID: r3}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(aws.StringValue(gen["key"].M["pk"].S)).Should().Equal("a:b").
+		If(aws.StringValue(gen["key"].M["sk"].S)).Should().Equal("c")
+
+	gen, err = dynamodbattribute.MarshalMap(Struct{Key: iri.SortKeyID{ID: r1}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(aws.StringValue(gen["key"].M["pk"].S)).Should().Equal(r1.PartitionKey()).
+		If(aws.StringValue(gen["key"].M["sk"].S)).Should().Equal(r1.SortKey()).
+		If(aws.StringValue(gen["key"].M["pk"].S)).Should().Equal("a").
+		If(aws.StringValue(gen["key"].M["sk"].S)).Should().Equal("")
+
+	gen, err = dynamodbattribute.MarshalMap(Struct{Key: iri.SortKeyID{ID: r0}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(aws.BoolValue(gen["key"].NULL)).Should().Equal(true)
+}
+
+func TestPrefixSetID(t *testing.T) {
+	type Struct struct {
+		Key   iri.PrefixSetID `dynamodbav:"key"`
+		Title string          `dynamodbav:"title"`
+	}
+
+	test := []Struct{
+		{Key: iri.PrefixSetID{ID: iri.New("")}, Title: "t"},
+		{Key: iri.PrefixSetID{ID: iri.New("a")}, Title: "t"},
+		{Key: iri.PrefixSetID{ID: iri.New("a:b")}, Title: "t"},
+		{Key: iri.PrefixSetID{ID: iri.New("a:b:c")}, Title: "t"},
+	}
+
+	for _, eg := range test {
+		in := Struct{}
+
+		gen, err1 := dynamodbattribute.MarshalMap(eg)
+		err2 := dynamodbattribute.UnmarshalMap(gen, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(eg).Should().Equal(in)
+	}
+}
+
+func TestPrefixSetIDContent(t *testing.T) {
+	type Struct struct {
+		Key iri.PrefixSetID `dynamodbav:"key"`
+	}
+
+	gen, err := dynamodbattribute.MarshalMap(Struct{Key: iri.PrefixSetID{ID: r3}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(aws.StringValueSlice(gen["key"].SS)).Should().Equal([]string{"a", "a:b", "a:b:c"})
+
+	gen, err = dynamodbattribute.MarshalMap(Struct{Key: iri.PrefixSetID{ID: r0}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(aws.BoolValue(gen["key"].NULL)).Should().Equal(true)
+}
+
+func TestListID(t *testing.T) {
+	type Struct struct {
+		Key   iri.ListID `dynamodbav:"key"`
+		Title string     `dynamodbav:"title"`
+	}
+
+	test := []Struct{
+		{Key: iri.ListID{ID: iri.New("")}, Title: "t"},
+		{Key: iri.ListID{ID: iri.New("a")}, Title: "t"},
+		{Key: iri.ListID{ID: iri.New("a:b")}, Title: "t"},
+		{Key: iri.ListID{ID: iri.Join("a", "12:00", "c")}, Title: "t"},
+	}
+
+	for _, eg := range test {
+		in := Struct{}
+
+		gen, err1 := dynamodbattribute.MarshalMap(eg)
+		err2 := dynamodbattribute.UnmarshalMap(gen, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(eg).Should().Equal(in)
+	}
+}
+
+func TestListIDSegments(t *testing.T) {
+	type Struct struct {
+		Key iri.ListID `dynamodbav:"key"`
+	}
+
+	gen, err := dynamodbattribute.MarshalMap(Struct{Key: iri.ListID{ID: r2}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(len(gen["key"].L)).Should().Equal(2).
+		If(aws.StringValue(gen["key"].L[0].S)).Should().Equal("a").
+		If(aws.StringValue(gen["key"].L[1].S)).Should().Equal("b")
+
+	gen, err = dynamodbattribute.MarshalMap(Struct{Key: iri.ListID{ID: r0}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(gen["key"].L != nil).Should().Equal(true).
+		If(len(gen["key"].L)).Should().Equal(0)
+}
+
+func TestEmptyStringID(t *testing.T) {
+	type Struct struct {
+		Key   iri.EmptyStringID `dynamodbav:"key"`
+		Title string            `dynamodbav:"title"`
+	}
+
+	test := []Struct{
+		{Key: iri.EmptyStringID{ID: iri.New("")}, Title: "t"},
+		{Key: iri.EmptyStringID{ID: iri.New("a")}, Title: "t"},
+		{Key: iri.EmptyStringID{ID: iri.New("a:b:c")}, Title: "t"},
+	}
+
+	for _, eg := range test {
+		in := Struct{}
+
+		gen, err1 := dynamodbattribute.MarshalMap(eg)
+		err2 := dynamodbattribute.UnmarshalMap(gen, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(eg).Should().Equal(in)
+	}
+
+	gen, err := dynamodbattribute.MarshalMap(Struct{Key: iri.EmptyStringID{ID: r0}})
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(gen["key"].NULL == nil).Should().Equal(true).
+		If(gen["key"].S != nil).Should().Equal(true).
+		If(aws.StringValue(gen["key"].S)).Should().Equal("")
+}
+
+func TestEmptyStringIDLegacy(t *testing.T) {
+	type Struct struct {
+		Key iri.EmptyStringID `dynamodbav:"key"`
+	}
+
+	test := []map[string]*dynamodb.AttributeValue{
+		{"key": {NULL: aws.Bool(true)}},
+		{"key": {S: aws.String("")}},
+	}
+
+	for _, eg := range test {
+		in := Struct{Key: iri.EmptyStringID{ID: r3}}
+		err := dynamodbattribute.UnmarshalMap(eg, &in)
+
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(in.Key.IRI.IsEmpty()).Should().Equal(true)
+	}
+}
+
+func TestMaxSegmentsDynamo(t *testing.T) {
+	defer func(n int) { iri.MaxSegments = n }(iri.MaxSegments)
+	iri.MaxSegments = 2
+
+	var (
+		sk iri.SortKeyID
+		ps iri.PrefixSetID
+		ls iri.ListID
+		es iri.EmptyStringID
+	)
+
+	test := map[interface{}][]interface{}{
+		&sk: {iri.SortKeyID{ID: r2}, iri.SortKeyID{ID: r3}},
+		&ps: {iri.PrefixSetID{ID: r2}, iri.PrefixSetID{ID: r3}},
+		&ls: {iri.ListID{ID: r2}, iri.ListID{ID: r3}},
+		&es: {iri.EmptyStringID{ID: r2}, iri.EmptyStringID{ID: r3}},
+	}
+
+	for val, eg := range test {
+		ok, _ := dynamodbattribute.Marshal(eg[0])
+		bad, _ := dynamodbattribute.Marshal(eg[1])
+
+		it.Ok(t).
+			If(dynamodbattribute.Unmarshal(ok, val)).Should().Equal(nil).
+			If(dynamodbattribute.Unmarshal(bad, val)).ShouldNot().Equal(nil)
+	}
+}
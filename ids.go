@@ -0,0 +1,81 @@
+package iri
+
+import "sort"
+
+/*
+
+IDs is a sequence of identities, it implements sort.Interface using
+lexicographic order of IRIs (see Compare).
+
+  sort.Sort(iri.IDs(seq))
+*/
+type IDs []ID
+
+// Len is number of identities in the sequence
+func (ids IDs) Len() int { return len(ids) }
+
+// Less reports whether identity i is ordered before j
+func (ids IDs) Less(i, j int) bool { return ids[i].Compare(ids[j]) < 0 }
+
+// Swap identities i and j
+func (ids IDs) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+
+/*
+
+Search returns index of target in the sorted sequence, -1 if it is absent
+*/
+func (ids IDs) Search(target ID) int {
+	i := sort.Search(len(ids), func(i int) bool { return ids[i].Compare(target) >= 0 })
+	if i < len(ids) && ids[i].Compare(target) == 0 {
+		return i
+	}
+
+	return -1
+}
+
+/*
+
+Dedup removes duplicate identities preserving order of first occurrence.
+Identities are bucketed by Hash and compared segment-wise, no strings are
+allocated.
+*/
+func Dedup(ids []ID) []ID {
+	seen := make(map[uint64][]int, len(ids))
+	uniq := make([]ID, 0, len(ids))
+
+	for _, id := range ids {
+		h := id.Hash()
+		dup := false
+		for _, i := range seen[h] {
+			if uniq[i].Eq(id) {
+				dup = true
+				break
+			}
+		}
+
+		if !dup {
+			seen[h] = append(seen[h], len(uniq))
+			uniq = append(uniq, id)
+		}
+	}
+
+	return uniq
+}
+
+/*
+
+GroupByPrefix buckets identities by their prefix of first rank segments,
+the key of bucket is compact prefix string. Identities shorter than rank
+are grouped under their full IRI.
+
+  iri.GroupByPrefix(seq, 2) ⟼ {"tenant:a": [...], "tenant:b": [...]}
+*/
+func GroupByPrefix(ids []ID, rank int) map[string][]ID {
+	groups := map[string][]ID{}
+	for _, id := range ids {
+		key := id.Truncate(rank).String()
+		groups[key] = append(groups[key], id)
+	}
+
+	return groups
+}
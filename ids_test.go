@@ -0,0 +1,66 @@
+package iri_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestIDsSort(t *testing.T) {
+	seq := iri.IDs{r4, iri.New("b"), r1, r5, r0, iri.New("a:c"), r3, r2}
+	sort.Sort(seq)
+
+	it.Ok(t).
+		If(seq).Should().Equal(iri.IDs{r0, r1, r2, r3, r4, r5, iri.New("a:c"), iri.New("b")})
+
+	for i := range seq {
+		for j := range seq {
+			it.Ok(t).If(seq.Less(i, j)).Should().Equal(seq[i].Compare(seq[j]) < 0)
+		}
+	}
+}
+
+func TestIDsSearch(t *testing.T) {
+	seq := iri.IDs{r0, r1, r2, r3, r4, r5, iri.New("b")}
+
+	for i, x := range seq {
+		it.Ok(t).If(seq.Search(x)).Should().Equal(i)
+	}
+
+	it.Ok(t).
+		If(seq.Search(iri.New("a:c"))).Should().Equal(-1).
+		If(seq.Search(iri.New("c"))).Should().Equal(-1).
+		If(iri.IDs{}.Search(r1)).Should().Equal(-1)
+}
+
+func TestDedup(t *testing.T) {
+	it.Ok(t).
+		If(iri.Dedup([]iri.ID{r3, r1, r3, r2, r1, r3})).Should().Equal([]iri.ID{r3, r1, r2}).
+		If(iri.Dedup([]iri.ID{r0, r1, r0, iri.New(""), r1})).Should().Equal([]iri.ID{r0, r1}).
+		If(iri.Dedup([]iri.ID{r1, r2, r3})).Should().Equal([]iri.ID{r1, r2, r3}).
+		If(iri.Dedup([]iri.ID{})).Should().Equal([]iri.ID{})
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	a1 := iri.New("tenant:a:order:1")
+	a2 := iri.New("tenant:a:order:2")
+	b1 := iri.New("tenant:b:order:1")
+	ta := iri.New("tenant:a")
+	t0 := iri.New("tenant")
+
+	rank2 := map[string][]iri.ID{
+		"tenant:a": {a1, a2, ta},
+		"tenant:b": {b1},
+		"tenant":   {t0},
+		"":         {r0},
+	}
+	rank0 := map[string][]iri.ID{
+		"": {a1, b1},
+	}
+
+	it.Ok(t).
+		If(iri.GroupByPrefix([]iri.ID{a1, b1, t0, a2, ta, r0}, 2)).Should().Equal(rank2).
+		If(iri.GroupByPrefix([]iri.ID{a1, b1}, 0)).Should().Equal(rank0)
+}
@@ -1,14 +1,21 @@
 package iri
 
 import (
+	"crypto/subtle"
+	"database/sql/driver"
+	"encoding/base32"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Thing is "interface tag" allows usage of IRI abstraction in other interfaces
@@ -25,7 +32,7 @@ ID is type tagging built over IRI type, unique identity of a thing.
 	}
 */
 type ID struct {
-	IRI IRI `dynamodbav:"id" json:"id"`
+	IRI IRI `dynamodbav:"id" json:"id" yaml:"id" xml:"id" msgpack:"id"`
 }
 
 /*
@@ -38,7 +45,153 @@ func New(iri string, args ...interface{}) ID {
 
 /*
 
-Prefix return IRI prefix
+NewWithSep builds IRI from string of segments delimited by the separator
+
+  iri.NewWithSep("/", "a/b/c") ⟼ a:b:c
+*/
+func NewWithSep(sep string, s string) ID {
+	if s == "" || sep == "" {
+		return ID{IRI: IRI{Seq: []string{s}}}
+	}
+
+	return ID{IRI: IRI{Seq: strings.Split(s, sep)}}
+}
+
+/*
+
+NewTrim parses a compact IRI string, the whitespace surrounding the input
+and each segment is removed. Unlike New, which preserves spaces, it is
+lenient to IRIs pasted from config files or CSV.
+
+  iri.NewTrim(" a : b ") ⟼ [a, b]
+*/
+func NewTrim(s string) ID {
+	seq := split(strings.TrimSpace(s))
+	for i, x := range seq {
+		seq[i] = strings.TrimSpace(x)
+	}
+
+	return ID{IRI: IRI{Seq: seq}}
+}
+
+/*
+
+Parse strictly parses a compact IRI string. Unlike New, it fails on
+leading or trailing separator, empty or whitespace-only segments and
+reports position of the offending segment. The empty string is the empty IRI.
+The grammar of compact IRI:
+
+  iri     = "" | segment *( ":" segment )
+  segment = 1*( char | "%3A" | "%23" | "%25" )  ; not whitespace-only
+  char    = any byte except ":"
+
+The escape sequences are decoded as ":", "#" and "%", other "%" are kept as-is.
+Parse never panics, for any accepted input Parse(id.String()) equals id.
+*/
+func Parse(iri string) (ID, error) {
+	if iri == "" {
+		return ID{IRI: IRI{Seq: []string{""}}}, nil
+	}
+
+	seq := strings.Split(iri, ":")
+	for i, x := range seq {
+		if err := validSegment(i, x); err != nil {
+			return ID{}, err
+		}
+		seq[i] = unescape(x)
+	}
+
+	return ID{IRI: IRI{Seq: seq}}, nil
+}
+
+/*
+
+MustParse is like Parse but panics if IRI string is malformed. It is
+intended for package-level variables and tests.
+*/
+func MustParse(iri string) ID {
+	id, err := Parse(iri)
+	if err != nil {
+		panic(fmt.Sprintf("iri: MustParse(%q): %v", iri, err))
+	}
+
+	return id
+}
+
+/*
+
+Join builds IRI from segments. Unlike New, segments are not split on
+separator, the segment might contain ":".
+
+  iri.Join("user", "2023:01", "42") ⟼ [user, 2023:01, 42]
+*/
+func Join(segments ...string) ID {
+	if len(segments) == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}
+	}
+
+	return ID{IRI: IRI{Seq: append([]string{}, segments...)}}
+}
+
+/*
+
+FromSegments builds IRI from slice of segments, it is equivalent to Join.
+The slice is copied, later mutation of input does not change the IRI.
+*/
+func FromSegments(seq []string) ID {
+	return Join(seq...)
+}
+
+/*
+
+LongestValid parses the longest valid prefix of compact IRI string using same
+segment rules as Parse.
+The valid prefix stops at the first invalid segment, the unparsed
+remainder is the tail of input after the prefix, including separator.
+
+  iri.LongestValid("a:b::c") ⟼ a:b, "::c"
+*/
+func LongestValid(s string) (ID, string) {
+	seq := strings.Split(s, ":")
+
+	n := 0
+	for n < len(seq) && validSegment(n, seq[n]) == nil {
+		n++
+	}
+
+	if n == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}, s
+	}
+
+	prefix := strings.Join(seq[:n], ":")
+	for i := 0; i < n; i++ {
+		seq[i] = unescape(seq[i])
+	}
+
+	return ID{IRI: IRI{Seq: seq[:n:n]}}, s[len(prefix):]
+}
+
+/*
+
+FromColumns builds IRI from depth and segments stored in separate columns.
+It fails if depth does not match number of segments. The segments are
+copied, the IRI does not share memory with input slice.
+*/
+func FromColumns(depth int, segments []string) (ID, error) {
+	if depth != len(segments) {
+		return ID{}, fmt.Errorf("iri: depth %d does not match %d segments", depth, len(segments))
+	}
+
+	if depth == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}, nil
+	}
+
+	return ID{IRI: IRI{Seq: append([]string{}, segments...)}}, nil
+}
+
+/*
+
+Prefix return IRI prefix, negative rank counts from the root
 */
 func (iri ID) Prefix(rank ...int) string {
 	return iri.IRI.Prefix(rank...)
@@ -46,225 +199,1646 @@ func (iri ID) Prefix(rank ...int) string {
 
 /*
 
-Suffix return IRI suffix
+Suffix return IRI suffix, negative rank counts from the root
+*/
+func (iri ID) Suffix(rank ...int) string {
+	return iri.IRI.Suffix(rank...)
+}
+
+/*
+
+PartitionKey returns IRI prefix, it is partition key of DynamoDB table,
+see IRI.PartitionKey
+*/
+func (iri ID) PartitionKey() string {
+	return iri.IRI.PartitionKey()
+}
+
+/*
+
+SortKey returns IRI suffix, it is sort key of DynamoDB table,
+see IRI.SortKey
+*/
+func (iri ID) SortKey() string {
+	return iri.IRI.SortKey()
+}
+
+/*
+
+Parent returns a IRI that is prefix of this one, negative rank counts from the root
+*/
+func (iri ID) Parent(rank ...int) ID {
+	return ID{IRI: iri.IRI.Parent(rank...)}
+}
+
+/*
+
+Scheme returns the first segment of IRI
+*/
+func (iri ID) Scheme() string {
+	return iri.IRI.Scheme()
+}
+
+/*
+
+WithScheme returns a IRI with the first segment replaced
+*/
+func (iri ID) WithScheme(scheme string) ID {
+	return ID{IRI: iri.IRI.WithScheme(scheme)}
+}
+
+/*
+
+IsAbsolute returns true if the first segment of IRI is one of known schemes
+*/
+func (iri ID) IsAbsolute(schemes ...string) bool {
+	return iri.IRI.IsAbsolute(schemes...)
+}
+
+/*
+
+IsRelative returns true if IRI is not absolute
+*/
+func (iri ID) IsRelative(schemes ...string) bool {
+	return iri.IRI.IsRelative(schemes...)
+}
+
+/*
+
+LocalName returns the last segment of IRI
+*/
+func (iri ID) LocalName() string {
+	return iri.IRI.LocalName()
+}
+
+/*
+
+Namespace returns IRI without the last segment, it is equivalent to Parent
+*/
+func (iri ID) Namespace() ID {
+	return ID{IRI: iri.IRI.Namespace()}
+}
+
+/*
+
+Truncate returns a IRI limited to first maxDepth segments
+*/
+func (iri ID) Truncate(maxDepth int) ID {
+	return ID{IRI: iri.IRI.Truncate(maxDepth)}
+}
+
+/*
+
+SplitAt cuts IRI after first rank segments into head and tail
+*/
+func (iri ID) SplitAt(rank int) (head ID, tail ID) {
+	h, t := iri.IRI.SplitAt(rank)
+	return ID{IRI: h}, ID{IRI: t}
+}
+
+/*
+
+TrimPrefix returns IRI without the prefix and true if prefix is matched
+*/
+func (iri ID) TrimPrefix(prefix ID) (ID, bool) {
+	rel, ok := iri.IRI.TrimPrefix(prefix.IRI)
+	return ID{IRI: rel}, ok
+}
+
+/*
+
+Ancestors returns all proper prefixes of IRI ordered from root, the IRI
+itself and the empty root are not included.
+
+  iri.New("a:b:c").Ancestors() ⟼ [a, a:b]
+*/
+func (iri ID) Ancestors() []ID {
+	lineage := iri.Lineage()
+	if len(lineage) == 0 {
+		return lineage
+	}
+
+	return lineage[:len(lineage)-1]
+}
+
+/*
+
+Lineage returns all prefixes of IRI ordered from root, including the IRI itself.
+
+  iri.New("a:b:c").Lineage() ⟼ [a, a:b, a:b:c]
+*/
+func (iri ID) Lineage() []ID {
+	seq := iri.IRI.seq()
+	lineage := make([]ID, len(seq))
+	for i := range seq {
+		lineage[i] = ID{IRI: IRI{Seq: append([]string{}, seq[:i+1]...)}}
+	}
+
+	return lineage
+}
+
+/*
+
+Prefixes returns compact form of all prefixes of IRI ordered from root,
+including the IRI itself. The empty IRI has none.
+
+  iri.New("a:b:c").Prefixes() ⟼ ["a", "a:b", "a:b:c"]
+*/
+func (iri ID) Prefixes() []string {
+	seq := iri.IRI.seq()
+	prefixes := make([]string, len(seq))
+	for i := range seq {
+		prefixes[i] = join(seq[:i+1])
+	}
+
+	return prefixes
+}
+
+/*
+
+Suffixes returns compact form of all suffixes of IRI ordered from leaf,
+including the IRI itself. The empty IRI has none.
+
+  iri.New("a:b:c").Suffixes() ⟼ ["c", "b:c", "a:b:c"]
+*/
+func (iri ID) Suffixes() []string {
+	seq := iri.IRI.seq()
+	suffixes := make([]string, len(seq))
+	for i := range seq {
+		suffixes[i] = join(seq[len(seq)-i-1:])
+	}
+
+	return suffixes
+}
+
+/*
+
+Walk visits prefixes of IRI ordered from root including the IRI itself,
+the empty root is not visited, Walk calls fn Len() times. It stops if fn
+returns false. Prefixes share memory with IRI, they are not allocated.
+*/
+func (iri ID) Walk(fn func(prefix ID) bool) {
+	seq := iri.IRI.seq()
+	for i := range seq {
+		if !fn(ID{IRI: IRI{Seq: seq[: i+1 : i+1]}}) {
+			return
+		}
+	}
+}
+
+/*
+
+Heir returns a IRI that descendant of this one.
+*/
+func (iri ID) Heir(segment string) ID {
+	return ID{IRI: iri.IRI.Heir(segment)}
+}
+
+/*
+
+Sibling returns a IRI that shares parent with this one
+*/
+func (iri ID) Sibling(name string) ID {
+	return ID{IRI: iri.IRI.Sibling(name)}
+}
+
+/*
+
+Heirs returns a IRI that descendant of this one by many levels.
+*/
+func (iri ID) Heirs(segments ...string) ID {
+	return ID{IRI: iri.IRI.Heirs(segments...)}
+}
+
+/*
+
+SetSegment returns a IRI with segment at rank (counting from root) replaced.
+*/
+func (iri ID) SetSegment(rank int, value string) ID {
+	return ID{IRI: iri.IRI.SetSegment(rank, value)}
+}
+
+/*
+
+Reverse returns a IRI with segments in reverse order
+*/
+func (iri ID) Reverse() ID {
+	return ID{IRI: iri.IRI.Reverse()}
+}
+
+/*
+
+WithSuffix returns a IRI with last rank segments replaced by new ones
+*/
+func (iri ID) WithSuffix(rank int, segments ...string) ID {
+	return ID{IRI: iri.IRI.WithSuffix(rank, segments...)}
+}
+
+/*
+
+Append returns a IRI that concatenates other one to this one.
+*/
+func (iri ID) Append(other ID) ID {
+	return ID{IRI: iri.IRI.Append(other.IRI)}
+}
+
+/*
+
+Cons returns a IRI that prepends the segment to this one.
+*/
+func (iri ID) Cons(head string) ID {
+	return ID{IRI: iri.IRI.Cons(head)}
+}
+
+/*
+
+Snoc returns a IRI that appends the segment to this one, it is symmetric to Cons.
+*/
+func (iri ID) Snoc(tail string) ID {
+	return ID{IRI: iri.IRI.Snoc(tail)}
+}
+
+/*
+
+RelTo returns a relative IRI that navigates from this one to the target.
+*/
+func (iri ID) RelTo(target ID) (ID, error) {
+	rel, err := iri.IRI.RelTo(target.IRI)
+	if err != nil {
+		return ID{}, err
+	}
+
+	return ID{IRI: rel}, nil
+}
+
+/*
+
+ValidateLengths checks byte length of IRI segments against per-position limits
+*/
+func (iri ID) ValidateLengths(limits []int) error {
+	return iri.IRI.ValidateLengths(limits)
+}
+
+/*
+
+Path converts IRI to the path, joins IRI segments
+*/
+func (iri ID) Path() string {
+	return path.Join(iri.IRI.Seq...)
+}
+
+/*
+
+JoinWith joins IRI segments with the separator, segments are not escaped.
+The empty IRI is the empty string.
+
+  iri.New("a:b:c").JoinWith(".") ⟼ a.b.c
+*/
+func (iri ID) JoinWith(sep string) string {
+	return strings.Join(iri.IRI.seq(), sep)
+}
+
+/*
+
+FilePath converts IRI to the file system path, joins IRI segments with
+os.PathSeparator. Segments are sanitized to prevent path traversal: path
+separators, ":" and "%" are percent-encoded, "." and ".." are encoded as
+"%2E". The ":" would make drive-relative path or NTFS alternate data stream
+on Windows.
+
+  iri.Join("a", "..", "b/c").FilePath() ⟼ a/%2E%2E/b%2Fc
+  iri.Join("C:", "b").FilePath()        ⟼ C%3A/b
+*/
+func (iri ID) FilePath() string {
+	seq := make([]string, len(iri.IRI.Seq))
+	for i, x := range iri.IRI.Seq {
+		switch x {
+		case ".", "..":
+			seq[i] = strings.Repeat("%2E", len(x))
+		default:
+			seq[i] = filePathEscaper.Replace(x)
+		}
+	}
+
+	return filepath.Join(seq...)
+}
+
+/*
+
+ChildRange returns bounds of string range scan over descendants of IRI,
+the lower bound "prefix:" is inclusive, the upper bound "prefix;" is
+exclusive (";" is the byte next to separator ":"). The range captures all
+descendants of IRI at any depth but neither IRI itself nor its siblings.
+The empty IRI is not bounded, both bounds are empty strings.
+
+  iri.New("a:b").ChildRange() ⟼ "a:b:", "a:b;"
+*/
+func (iri ID) ChildRange() (lo, hi string) {
+	if iri.IRI.IsEmpty() {
+		return "", ""
+	}
+
+	s := iri.IRI.String()
+	return s + ":", s + ";"
+}
+
+/*
+
+String returns compact IRI "prefix:suffix", ID implements fmt.Stringer.
+Note: the method is promoted to structs that embed ID.
+*/
+func (iri ID) String() string {
+	return iri.IRI.String()
+}
+
+/*
+
+WriteTo writes compact IRI to the writer, ID implements io.WriterTo.
+*/
+func (iri ID) WriteTo(w io.Writer) (int64, error) {
+	return iri.IRI.WriteTo(w)
+}
+
+/*
+
+ToIRI converts ID to IRI type
+*/
+func (iri ID) ToIRI() *IRI {
+	return &iri.IRI
+}
+
+/*
+
+Identity return unique identity, required by Thing interface
+*/
+func (iri ID) Identity() ID {
+	return iri
+}
+
+/*
+
+EqThing return true if IRI equals to identity of the thing, nil thing is
+never equal.
+*/
+func (iri ID) EqThing(thing Thing) bool {
+	return thing != nil && iri.Eq(thing.Identity())
+}
+
+/*
+
+Same return true if things have equal identities, nil thing is never same.
+
+  type User struct{ iri.ID }
+  iri.Same(User{iri.New("u:1")}, User{iri.New("u:1")}) ⟼ true
+*/
+func Same(a, b Thing) bool {
+	return a != nil && b != nil && a.Identity().Eq(b.Identity())
+}
+
+/*
+
+EqConstantTime return true if IRI equals, see IRI.EqConstantTime
+*/
+func (iri ID) EqConstantTime(x ID) bool {
+	return iri.IRI.EqConstantTime(x.IRI)
+}
+
+/*
+
+Eq return true if IRI equals
+*/
+func (iri ID) Eq(x ID) bool {
+	return iri.IRI.Eq(x.IRI)
+}
+
+/*
+
+EqIgnoreCase return true if IRI equals under Unicode case-folding
+*/
+func (iri ID) EqIgnoreCase(x ID) bool {
+	return iri.IRI.EqIgnoreCase(x.IRI)
+}
+
+/*
+
+EqFoldPrefix return true if IRI equals, first n segments are compared case-insensitive
+*/
+func (iri ID) EqFoldPrefix(n int, x ID) bool {
+	return iri.IRI.EqFoldPrefix(n, x.IRI)
+}
+
+/*
+
+Normalize returns a IRI without empty segments
+*/
+func (iri ID) Normalize() ID {
+	return ID{IRI: iri.IRI.Normalize()}
+}
+
+/*
+
+NormalizeUnicode returns a IRI with segments in Unicode NFC form
+*/
+func (iri ID) NormalizeUnicode() ID {
+	return ID{IRI: iri.IRI.NormalizeUnicode()}
+}
+
+/*
+
+Map returns a IRI with fn applied to each segment
+*/
+func (iri ID) Map(fn func(i int, segment string) string) ID {
+	return ID{IRI: iri.IRI.Map(fn)}
+}
+
+/*
+
+ToLower returns a IRI with all segments in lower case
+*/
+func (iri ID) ToLower() ID {
+	return ID{IRI: iri.IRI.ToLower()}
+}
+
+/*
+
+EqIgnoring return true if IRI equals, segments at given positions are ignored
+*/
+func (iri ID) EqIgnoring(positions []int, x ID) bool {
+	return iri.IRI.EqIgnoring(positions, x.IRI)
+}
+
+/*
+
+HasPrefix return true if IRI starts with the prefix
+*/
+func (iri ID) HasPrefix(prefix ID) bool {
+	return iri.IRI.HasPrefix(prefix.IRI)
+}
+
+/*
+
+CommonPrefixLen returns number of leading segments shared with x
+*/
+func (iri ID) CommonPrefixLen(x ID) int {
+	return iri.IRI.CommonPrefixLen(x.IRI)
+}
+
+/*
+
+Contains return true if other IRI is strict descendant of this one
+*/
+func (iri ID) Contains(other ID) bool {
+	return iri.IRI.Contains(other.IRI)
+}
+
+/*
+
+IsAncestorOf return true if IRI is strict ancestor of x
+*/
+func (iri ID) IsAncestorOf(x ID) bool {
+	return iri.IRI.IsAncestorOf(x.IRI)
+}
+
+/*
+
+IsDescendantOf return true if IRI is strict descendant of x
+*/
+func (iri ID) IsDescendantOf(x ID) bool {
+	return iri.IRI.IsDescendantOf(x.IRI)
+}
+
+/*
+
+Compare returns -1, 0 or +1 as lexicographic order of IRIs
+*/
+func (iri ID) Compare(x ID) int {
+	return iri.IRI.Compare(x.IRI)
+}
+
+/*
+
+CompareWith compares IRIs using per-position segment comparator
+*/
+func (iri ID) CompareWith(x ID, cmp func(pos int, a, b string) int) int {
+	return iri.IRI.CompareWith(x.IRI, cmp)
+}
+
+/*
+
+IsEmpty return true if IRI has no segments
+*/
+func (iri ID) IsEmpty() bool {
+	return iri.IRI.IsEmpty()
+}
+
+/*
+
+IsRoot return true if IRI is the root of hierarchy, it is alias to IsEmpty
+*/
+func (iri ID) IsRoot() bool {
+	return iri.IRI.IsRoot()
+}
+
+/*
+
+Hash returns stable 64-bit non-cryptographic hash of IRI
+*/
+func (iri ID) Hash() uint64 {
+	return iri.IRI.Hash()
+}
+
+/*
+
+ShortID returns short display identity of IRI, 8 characters of lower case
+base32 (RFC 4648 alphabet) encoding the upper 40 bits of Hash. It is stable
+across runs, suitable for URLs, but not reversible to IRI. Distinct IRIs
+may collide, probability of any collision reaches 50% around 1.2 million
+IRIs (birthday bound of 40 bits), the ShortID is not a unique key.
+
+  iri.New("a:b").ShortID() ⟼ vnanpaqn
+*/
+func (iri ID) ShortID() string {
+	h := iri.IRI.Hash()
+	b := [5]byte{byte(h >> 56), byte(h >> 48), byte(h >> 40), byte(h >> 32), byte(h >> 24)}
+	return shortIDEncoding.EncodeToString(b[:])
+}
+
+/*
+
+Len returns number of segments, the empty IRI has none
+*/
+func (iri ID) Len() int {
+	return iri.IRI.Len()
+}
+
+/*
+
+Segments returns segments of IRI
+*/
+func (iri ID) Segments() []string {
+	return iri.IRI.Segments()
+}
+
+/*
+
+Segment returns segment at index i, false if index is out of range
+*/
+func (iri ID) Segment(i int) (string, bool) {
+	return iri.IRI.Segment(i)
+}
+
+/*
+
+Equal is canonical semantic equality of IRIs, it is same as a.Eq(b). It is
+recommended over structural equality (e.g. reflect.DeepEqual in test
+assertions), which depends on internal representation of IRI, e.g. the
+root is either IRI{} or IRI{Seq: []string{""}}.
+*/
+func Equal(a, b ID) bool {
+	return a.Eq(b)
+}
+
+/*
+
+CommonPrefix returns the longest prefix shared by IRIs, whole segments are compared.
+
+  iri.CommonPrefix(iri.New("a:b:c"), iri.New("a:b:d")) ⟼ a:b
+*/
+func CommonPrefix(a, b ID) ID {
+	n := commonPrefixLen(a.IRI.seq(), b.IRI.seq())
+	if n == 0 {
+		return ID{IRI: IRI{Seq: []string{""}}}
+	}
+
+	return ID{IRI: IRI{Seq: append([]string{}, a.IRI.Seq[:n]...)}}
+}
+
+/*
+
+Rel returns target IRI relative to the base, the base is removed from target.
+It fails if base is not a prefix of target.
+
+  iri.Rel(iri.New("a:b"), iri.New("a:b:c:d")) ⟼ c:d
+*/
+func Rel(base, target ID) (ID, error) {
+	if !target.HasPrefix(base) {
+		return ID{}, fmt.Errorf("iri: %q is not prefix of %q", base.String(), target.String())
+	}
+
+	_, rel := target.SplitAt(base.Len())
+	return rel, nil
+}
+
+/*
+
+SegmentDistance returns the edit distance between IRIs measured in whole
+segments, it counts the minimal number of segment insertions, deletions or
+substitutions required to turn one IRI into another.
+
+  iri.SegmentDistance(iri.New("a:b:c"), iri.New("a:x:c")) ⟼ 1
+*/
+func SegmentDistance(a, b ID) int {
+	sa, sb := a.IRI.seq(), b.IRI.seq()
+	row := make([]int, len(sb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(sa); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(sb); j++ {
+			cost := 1
+			if sa[i-1] == sb[j-1] {
+				cost = 0
+			}
+
+			next := diag + cost
+			if row[j]+1 < next {
+				next = row[j] + 1
+			}
+			if row[j-1]+1 < next {
+				next = row[j-1] + 1
+			}
+			diag, row[j] = row[j], next
+		}
+	}
+
+	return row[len(sb)]
+}
+
+/*
+
+Value implements driver.Valuer, see IRI.Value
+*/
+func (iri ID) Value() (driver.Value, error) {
+	return iri.IRI.Value()
+}
+
+/*
+
+Scan implements sql.Scanner, see IRI.Scan
+*/
+func (iri *ID) Scan(src interface{}) error {
+	return iri.IRI.Scan(src)
+}
+
+/*
+
+IRI is Internationalized Resource Identifier
+https://en.wikipedia.org/wiki/Internationalized_Resource_Identifier
+*/
+type IRI struct {
+	Seq []string
+}
+
+/*
+
+MaxSegments limits number of segments of IRI decoded from untrusted input
+by UnmarshalJSON and UnmarshalDynamoDBAttributeValue, decoding fails if IRI
+is deeper. It prevents allocation of huge segment slices by payloads like
+"a:a:a:...". Zero or negative value disables the limit.
+*/
+var MaxSegments = 256
+
+/*
+
+NewIRI builds compact IRI from string. The "%3A", "%23" and "%25" sequences
+within segment are decoded as ":", "#" and "%", see IRI.String for details.
+*/
+func NewIRI(iri string, args ...interface{}) IRI {
+	val := iri
+
+	if len(args) > 0 {
+		val = fmt.Sprintf(iri, args...)
+	}
+
+	return IRI{
+		Seq: split(val),
+	}
+}
+
+/*
+
+NewIRIStrict builds compact IRI from string, it collapses empty segments so
+that IRI never contains them except the empty root. NewIRI keeps them, the
+empty root [""] is the IRI without segments while "a:" is the IRI with empty
+trailing segment [a, ""], which is not equal to "a".
+
+  iri.NewIRI("a::b:")       ⟼ [a, "", b, ""]
+  iri.NewIRIStrict("a::b:") ⟼ [a, b]
+  iri.NewIRIStrict(":")     ⟼ [""]
+*/
+func NewIRIStrict(iri string, args ...interface{}) IRI {
+	return NewIRI(iri, args...).Normalize()
+}
+
+/*
+
+Prefix return IRI prefix, rank segments are removed from the leaf.
+The negative rank counts segments from the root, the prefix is made of
+first -rank segments.
+
+  iri.New("a:b:c:d").Prefix(1)  ⟼ a:b:c
+  iri.New("a:b:c:d").Prefix(-1) ⟼ a
+*/
+func (iri IRI) Prefix(rank ...int) string {
+	r := rankOf(iri.Seq, rank)
+
+	if r == 1 && len(iri.Seq) == 1 {
+		return join(iri.Seq)
+	}
+
+	n := len(iri.Seq) - r
+	if n < 0 {
+		return ""
+	}
+
+	return join(iri.Seq[:n])
+}
+
+/*
+
+Suffix return IRI suffix, made of last rank segments. The negative rank
+counts segments from the root, the suffix follows first -rank segments.
+
+  iri.New("a:b:c:d").Suffix(1)  ⟼ d
+  iri.New("a:b:c:d").Suffix(-1) ⟼ b:c:d
+*/
+func (iri IRI) Suffix(rank ...int) string {
+	r := rankOf(iri.Seq, rank)
+
+	if len(iri.Seq) == 1 {
+		return ""
+	}
+
+	n := len(iri.Seq) - r
+	if n < 0 {
+		n = 0
+	}
+
+	return join(iri.Seq[n:len(iri.Seq)])
+}
+
+/*
+
+PartitionKey returns IRI prefix for building DynamoDB key expressions,
+it is same as Prefix(). The single segment IRI is partition key itself.
+SortKeyID stores the same pair, key expressions match its attributes.
+
+  iri.New("a:b:c") ⟼ pk: "a:b", sk: "c"
+  iri.New("a")     ⟼ pk: "a",   sk: ""
+*/
+func (iri IRI) PartitionKey() string {
+	return iri.Prefix()
+}
+
+/*
+
+SortKey returns IRI suffix for building DynamoDB key expressions,
+it is same as Suffix(). The single segment IRI has empty sort key,
+same as the sort key attribute of SortKeyID.
+*/
+func (iri IRI) SortKey() string {
+	return iri.Suffix()
+}
+
+/*
+
+Parent returns a IRI that is prefix of this one, see Prefix about rank.
+
+  iri.New("a:b:c:d").Parent(-2) ⟼ a:b
+*/
+func (iri IRI) Parent(rank ...int) IRI {
+	r := rankOf(iri.Seq, rank)
+
+	n := len(iri.Seq) - r
+	if n <= 0 {
+		return IRI{Seq: []string{""}}
+	}
+
+	return IRI{Seq: append([]string{}, iri.Seq[:n]...)}
+}
+
+/*
+
+Scheme returns the first segment of IRI, the empty IRI has none
+*/
+func (iri IRI) Scheme() string {
+	seg, _ := iri.Segment(0)
+	return seg
+}
+
+/*
+
+WithScheme returns a IRI with the first segment replaced. The scheme is
+prepended to the empty IRI, so it becomes the only segment.
+*/
+func (iri IRI) WithScheme(scheme string) IRI {
+	if iri.IsEmpty() {
+		return IRI{Seq: []string{scheme}}
+	}
+
+	return iri.SetSegment(0, scheme)
+}
+
+/*
+
+IsAbsolute returns true if the first segment of IRI is one of known schemes.
+Any non-empty first segment is a scheme if none are given.
+
+  iri.New("urn:isbn:1").IsAbsolute("urn", "http") ⟼ true
+  iri.New("isbn:1").IsAbsolute("urn", "http")     ⟼ false
+*/
+func (iri IRI) IsAbsolute(schemes ...string) bool {
+	scheme := iri.Scheme()
+	if len(schemes) == 0 {
+		return scheme != ""
+	}
+
+	for _, x := range schemes {
+		if x == scheme {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+
+IsRelative returns true if IRI is not absolute, it is negation of IsAbsolute
+*/
+func (iri IRI) IsRelative(schemes ...string) bool {
+	return !iri.IsAbsolute(schemes...)
+}
+
+/*
+
+LocalName returns the last segment of IRI, the empty IRI has none
+*/
+func (iri IRI) LocalName() string {
+	seq := iri.seq()
+	if len(seq) == 0 {
+		return ""
+	}
+
+	return seq[len(seq)-1]
+}
+
+/*
+
+Namespace returns IRI without the last segment, it is equivalent to Parent
+*/
+func (iri IRI) Namespace() IRI {
+	return iri.Parent()
+}
+
+/*
+
+Heir returns a IRI that descendant of this one.
+*/
+func (iri IRI) Heir(segment string) IRI {
+	if len(iri.Seq) == 1 && iri.Seq[0] == "" {
+		return IRI{Seq: []string{segment}}
+	}
+
+	return IRI{Seq: append(append([]string{}, iri.Seq...), segment)}
+}
+
+/*
+
+Sibling returns a IRI that shares parent with this one but has different
+local name, it is same as Parent().Heir(name). The single segment and
+the empty IRI have the empty parent, the name becomes the sole segment.
+
+  iri.New("a:b:c").Sibling("d") ⟼ a:b:d
+*/
+func (iri IRI) Sibling(name string) IRI {
+	return iri.Parent().Heir(name)
+}
+
+/*
+
+Truncate returns a IRI limited to first maxDepth segments, counting from root.
+It is no-op if IRI is not deeper than maxDepth, zero yields the empty IRI.
+
+  iri.New("a:b:c:d").Truncate(2) ⟼ a:b
+*/
+func (iri IRI) Truncate(maxDepth int) IRI {
+	if maxDepth >= iri.Len() {
+		return iri
+	}
+
+	head, _ := iri.SplitAt(maxDepth)
+	return head
+}
+
+/*
+
+SplitAt cuts IRI after first rank segments into head and tail. The rank is
+clamped to length of IRI, the head or tail is the empty IRI at boundaries.
+
+  iri.New("a:b:c:d").SplitAt(2) ⟼ a:b, c:d
+*/
+func (iri IRI) SplitAt(rank int) (head IRI, tail IRI) {
+	seq := iri.seq()
+	switch {
+	case rank < 0:
+		rank = 0
+	case rank > len(seq):
+		rank = len(seq)
+	}
+
+	return IRI{}.Heirs(seq[:rank]...), IRI{}.Heirs(seq[rank:]...)
+}
+
+/*
+
+TrimPrefix returns IRI with the prefix removed and true if the prefix is
+matched. Otherwise, the IRI is returned unchanged with false. The empty IRI
+is returned if prefix equals to IRI.
+
+  iri.New("a:b:c:d").TrimPrefix(iri.New("a:b")) ⟼ c:d, true
+*/
+func (iri IRI) TrimPrefix(prefix IRI) (IRI, bool) {
+	if !iri.HasPrefix(prefix) {
+		return iri, false
+	}
+
+	return IRI{}.Heirs(iri.seq()[len(prefix.seq()):]...), true
+}
+
+/*
+
+Heirs returns a IRI that descendant of this one by many levels.
+*/
+func (iri IRI) Heirs(segments ...string) IRI {
+	seq := iri.seq()
+	if len(seq)+len(segments) == 0 {
+		return IRI{Seq: []string{""}}
+	}
+
+	return IRI{Seq: append(append(make([]string, 0, len(seq)+len(segments)), seq...), segments...)}
+}
+
+/*
+
+SetSegment returns a IRI with segment at rank replaced. The rank is counted
+from root, starting at 0. Out of range rank is no-op, the IRI is returned as-is.
+*/
+func (iri IRI) SetSegment(rank int, value string) IRI {
+	seq := iri.seq()
+	if rank < 0 || rank >= len(seq) {
+		return iri
+	}
+
+	seq = append([]string{}, seq...)
+	seq[rank] = value
+	return IRI{Seq: seq}
+}
+
+/*
+
+Reverse returns a IRI with segments in reverse order, e.g. "www:example:com"
+becomes "com:example:www". Reverse is involution, applied twice it yields
+original IRI.
+*/
+func (iri IRI) Reverse() IRI {
+	seq := make([]string, len(iri.Seq))
+	for i, x := range iri.Seq {
+		seq[len(seq)-1-i] = x
+	}
+
+	return IRI{Seq: seq}
+}
+
+/*
+
+WithSuffix returns a IRI with last rank segments replaced by new ones. The
+rank is clamped to length of IRI, all segments are replaced if it exceeds.
+
+  iri.New("a:b:c").WithSuffix(1, "x", "y") ⟼ a:b:x:y
+*/
+func (iri IRI) WithSuffix(rank int, segments ...string) IRI {
+	if rank < 0 {
+		rank = 0
+	}
+
+	return iri.Parent(rank).Heirs(segments...)
+}
+
+/*
+
+Append returns a IRI that concatenates other one to this one, the empty
+IRI is neutral element.
+
+  iri.NewIRI("a:b").Append(iri.NewIRI("c:d")) ⟼ a:b:c:d
+*/
+func (iri IRI) Append(other IRI) IRI {
+	return iri.Heirs(other.seq()...)
+}
+
+/*
+
+Cons returns a IRI that prepends the segment to this one.
+*/
+func (iri IRI) Cons(head string) IRI {
+	if len(iri.Seq) == 1 && iri.Seq[0] == "" {
+		return IRI{Seq: []string{head}}
+	}
+
+	return IRI{Seq: append([]string{head}, iri.Seq...)}
+}
+
+/*
+
+Snoc returns a IRI that appends the segment to this one, it is symmetric to Cons.
+*/
+func (iri IRI) Snoc(tail string) IRI {
+	return iri.Heir(tail)
+}
+
+/*
+
+RelTo returns a relative IRI that navigates from this one to the target.
+The relative IRI is built via common prefix of IRIs. It starts with ".."
+segments, each one goes up to the parent, followed by forward segments
+of the target:
+
+  iri.NewIRI("a:b:c").RelTo(iri.NewIRI("a:b:x:y")) ⟼ "..:x:y"
+
+The IRI that contains ".." segment is not supported.
+*/
+func (iri IRI) RelTo(target IRI) (IRI, error) {
+	a, b := iri.seq(), target.seq()
+	for _, seq := range [][]string{a, b} {
+		for _, x := range seq {
+			if x == ".." {
+				return IRI{}, fmt.Errorf("iri: relative segment \"..\" is not supported")
+			}
+		}
+	}
+
+	n := commonPrefixLen(a, b)
+	rel := make([]string, 0, len(a)-n+len(b)-n)
+	for i := n; i < len(a); i++ {
+		rel = append(rel, "..")
+	}
+	rel = append(rel, b[n:]...)
+
+	if len(rel) == 0 {
+		return IRI{Seq: []string{""}}, nil
+	}
+
+	return IRI{Seq: rel}, nil
+}
+
+/*
+
+ValidateLengths checks that byte length of segment i does not exceed limits[i].
+The last limit is applied to any extra segments. Empty limits impose no constraints.
+*/
+func (iri IRI) ValidateLengths(limits []int) error {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	for i, x := range iri.seq() {
+		limit := limits[len(limits)-1]
+		if i < len(limits) {
+			limit = limits[i]
+		}
+
+		if len(x) > limit {
+			return fmt.Errorf("iri: segment %d is %d bytes, exceeds limit %d", i, len(x), limit)
+		}
+	}
+
+	return nil
+}
+
+/*
+
+String returns compact IRI "prefix:suffix". Segments are joined with ":",
+the separator within segment is percent-escaped as "%3A" so that the compact
+form is parsed back to same segments. The "%" is escaped as "%25" only if it
+starts an escape sequence ("%25", "%23", "%3A" or "%3a"), a lone "%" is
+verbatim. The "#" is verbatim, it is escaped by ID.WithFragment only.
+IRIs without embedded separators are serialized as-is. This escaping is
+the contract of all string-based serializers (JSON, text, YAML, XML, gob,
+msgpack, DynamoDB, protobuf helpers), the escaped form is decoded by New.
+
+The string is not memoized, IRI is a plain value compared structurally
+(e.g. reflect.DeepEqual), a hidden cache would break it. Single segment
+IRI does not allocate, otherwise the string is built with one allocation.
+*/
+func (iri IRI) String() string {
+	return join(iri.Seq)
+}
+
+/*
+
+WriteTo writes compact IRI "prefix:suffix" to the writer segment by segment,
+it produces same output as String without building an intermediate string.
+*/
+func (iri IRI) WriteTo(w io.Writer) (int64, error) {
+	var size int64
+	write := func(s string) error {
+		n, err := io.WriteString(w, s)
+		size += int64(n)
+		return err
+	}
+
+	for i, x := range iri.Seq {
+		if i > 0 {
+			if err := write(":"); err != nil {
+				return size, err
+			}
+		}
+
+		from := 0
+		for j := 0; j < len(x); j++ {
+			esc := escapeOf(x, j)
+			if esc == "" {
+				continue
+			}
+
+			if err := write(x[from:j]); err != nil {
+				return size, err
+			}
+			if err := write(esc); err != nil {
+				return size, err
+			}
+			from = j + 1
+		}
+
+		if err := write(x[from:]); err != nil {
+			return size, err
+		}
+	}
+
+	return size, nil
+}
+
+/*
+
+Eq return true if two IRI equals. All representations of the empty root are
+equal, the zero value IRI{}, IRI{Seq: []string{}} and IRI{Seq: []string{""}}.
+*/
+func (iri IRI) Eq(x IRI) bool {
+	a, b := iri.seq(), x.seq()
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+
+EqConstantTime return true if IRI equals, compact IRIs are compared with
+subtle.ConstantTimeCompare so that time does not depend on position of
+the first difference. Note that Eq is not constant time, it returns at
+the first mismatch. The length of IRI is still observable.
+*/
+func (iri IRI) EqConstantTime(x IRI) bool {
+	return subtle.ConstantTimeCompare([]byte(iri.String()), []byte(x.String())) == 1
+}
+
+/*
+
+EqIgnoreCase return true if IRI equals, all segments are compared with
+strings.EqualFold. Note that Eq is strictly byte-equal.
+*/
+func (iri IRI) EqIgnoreCase(x IRI) bool {
+	return iri.EqFoldPrefix(len(iri.Seq), x)
+}
+
+/*
+
+EqFoldPrefix return true if IRI equals, first n segments (e.g. scheme) are
+compared with strings.EqualFold, deeper segments are byte-equal.
+*/
+func (iri IRI) EqFoldPrefix(n int, x IRI) bool {
+	a, b := iri.seq(), x.seq()
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if i < n && strings.EqualFold(b[i], v) {
+			continue
+		}
+
+		if b[i] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+
+Normalize returns a IRI without empty segments, both trailing (e.g. "a:")
+and interior (e.g. "a::b") are removed. Eq of normalized IRIs treats "a:"
+and "a" as same resource.
 */
-func (iri ID) Suffix(rank ...int) string {
-	return iri.IRI.Suffix(rank...)
+func (iri IRI) Normalize() IRI {
+	seq := make([]string, 0, len(iri.Seq))
+	for _, x := range iri.Seq {
+		if x != "" {
+			seq = append(seq, x)
+		}
+	}
+
+	if len(seq) == 0 {
+		return IRI{Seq: []string{""}}
+	}
+
+	return IRI{Seq: seq}
 }
 
 /*
 
-Parent returns a IRI that is prefix of this one
+NormalizeUnicode returns a IRI with each segment normalized to Unicode NFC
+form. Canonically equivalent IRIs are equal after normalization, Eq
+remains byte-exact.
 */
-func (iri ID) Parent(rank ...int) ID {
-	return ID{IRI: iri.IRI.Parent(rank...)}
+func (iri IRI) NormalizeUnicode() IRI {
+	seq := make([]string, len(iri.Seq))
+	for i, x := range iri.Seq {
+		seq[i] = norm.NFC.String(x)
+	}
+
+	return IRI{Seq: seq}
 }
 
 /*
 
-Heir returns a IRI that descendant of this one.
+Map returns a IRI with fn applied to each segment, fn receives position of
+segment starting from root. The fn is not called for the empty IRI, it is
+mapped to itself.
+
+  iri.New("a:b:c").Map(func(i int, s string) string { return s + s }) ⟼ aa:bb:cc
 */
-func (iri ID) Heir(segment string) ID {
-	return ID{IRI: iri.IRI.Heir(segment)}
+func (iri IRI) Map(fn func(i int, segment string) string) IRI {
+	seq := iri.seq()
+	if seq == nil {
+		return IRI{Seq: []string{""}}
+	}
+
+	mapped := make([]string, len(seq))
+	for i, x := range seq {
+		mapped[i] = fn(i, x)
+	}
+
+	return IRI{Seq: mapped}
 }
 
 /*
 
-Path converts IRI to the path, joins IRI segments
+ToLower returns a IRI with each segment mapped to lower case by
+strings.ToLower. It is a simple Unicode lower case mapping, not locale-aware
+case folding (e.g. Turkish dotted I), see EqIgnoreCase for comparison.
 */
-func (iri ID) Path() string {
-	return path.Join(iri.IRI.Seq...)
+func (iri IRI) ToLower() IRI {
+	seq := make([]string, len(iri.Seq))
+	for i, x := range iri.Seq {
+		seq[i] = strings.ToLower(x)
+	}
+
+	return IRI{Seq: seq}
 }
 
 /*
 
-ToIRI converts ID to IRI type
+EqIgnoring return true if two IRI equals, segments at given positions are ignored.
+IRIs of different length are never equal. Positions out of range are not used.
 */
-func (iri ID) ToIRI() *IRI {
-	return &iri.IRI
+func (iri IRI) EqIgnoring(positions []int, x IRI) bool {
+	a, b := iri.seq(), x.seq()
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i, v := range a {
+		if b[i] != v && !hasPosition(positions, i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasPosition(positions []int, i int) bool {
+	for _, p := range positions {
+		if p == i {
+			return true
+		}
+	}
+	return false
 }
 
 /*
 
-Identity return unique identity, required by Thing interface
+HasPrefix return true if IRI starts with the prefix. Whole segments are
+compared, "a:bc" does not have prefix "a:b". The empty IRI is prefix of any.
 */
-func (iri ID) Identity() ID {
-	return iri
+func (iri IRI) HasPrefix(prefix IRI) bool {
+	return hasPrefix(iri.seq(), prefix.seq())
 }
 
 /*
 
-Eq return true if IRI equals
+CommonPrefixLen returns number of leading segments shared with x, it is
+length of CommonPrefix without allocating it. The empty IRI shares none.
+
+  iri.New("a:b:c").CommonPrefixLen(iri.New("a:b:d")) ⟼ 2
 */
-func (iri ID) Eq(x ID) bool {
-	return iri.IRI.Eq(x.IRI)
+func (iri IRI) CommonPrefixLen(x IRI) int {
+	return commonPrefixLen(iri.seq(), x.seq())
 }
 
 /*
 
-Segments returns segments of IRI
+Contains return true if other IRI is strict descendant of this one. Unlike
+inclusive HasPrefix, the IRI does not contain itself.
 */
-func (iri ID) Segments() []string {
-	return iri.IRI.Segments()
+func (iri IRI) Contains(other IRI) bool {
+	return len(other.seq()) > len(iri.seq()) && other.HasPrefix(iri)
 }
 
 /*
 
-IRI is Internationalized Resource Identifier
-https://en.wikipedia.org/wiki/Internationalized_Resource_Identifier
+IsAncestorOf return true if IRI is strict ancestor of x, the IRI is never
+ancestor of itself. a.IsAncestorOf(b) is same as b.IsDescendantOf(a).
 */
-type IRI struct {
-	Seq []string
+func (iri IRI) IsAncestorOf(x IRI) bool {
+	return iri.Contains(x)
 }
 
 /*
 
-NewIRI builds compact IRI from string
+IsDescendantOf return true if IRI is strict descendant of x, the IRI is
+never descendant of itself. a.IsDescendantOf(b) is same as b.IsAncestorOf(a).
 */
-func NewIRI(iri string, args ...interface{}) IRI {
-	val := iri
+func (iri IRI) IsDescendantOf(x IRI) bool {
+	return x.Contains(iri)
+}
 
-	if len(args) > 0 {
-		val = fmt.Sprintf(iri, args...)
-	}
+/*
 
-	return IRI{
-		Seq: strings.Split(val, ":"),
-	}
+Compare returns -1, 0 or +1 as lexicographic order of IRIs. Segments are
+compared one by one, the IRI is ordered before any of its descendants.
+*/
+func (iri IRI) Compare(x IRI) int {
+	return iri.CompareWith(x, func(_ int, a, b string) int { return strings.Compare(a, b) })
 }
 
 /*
 
-Prefix return IRI prefix
+CompareWith compares IRIs segment by segment using the comparator, which
+is called with position of segments. It returns -1, 0 or +1. Once common
+positions are exhausted, the shorter IRI is ordered first.
 */
-func (iri IRI) Prefix(rank ...int) string {
-	r := 1
-	if len(rank) > 0 {
-		r = rank[0]
+func (iri IRI) CompareWith(x IRI, cmp func(pos int, a, b string) int) int {
+	a, b := iri.seq(), x.seq()
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch c := cmp(i, a[i], b[i]); {
+		case c < 0:
+			return -1
+		case c > 0:
+			return 1
+		}
 	}
 
-	if r == 1 && len(iri.Seq) == 1 {
-		return strings.Join(iri.Seq, ":")
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
 	}
 
-	n := len(iri.Seq) - r
-	if n < 0 {
-		return ""
-	}
+	return 0
+}
+
+/*
 
-	return strings.Join(iri.Seq[:n], ":")
+IsEmpty return true if IRI has no segments
+*/
+func (iri IRI) IsEmpty() bool {
+	return len(iri.Seq) == 0 || (len(iri.Seq) == 1 && iri.Seq[0] == "")
 }
 
 /*
 
-Suffix return IRI suffix
+IsRoot return true if IRI is the root of hierarchy, it is alias to IsEmpty
 */
-func (iri IRI) Suffix(rank ...int) string {
-	r := 1
-	if len(rank) > 0 {
-		r = rank[0]
-	}
+func (iri IRI) IsRoot() bool {
+	return iri.IsEmpty()
+}
 
-	if len(iri.Seq) == 1 {
-		return ""
-	}
+/*
 
-	n := len(iri.Seq) - r
-	if n < 0 {
-		n = 0
+Hash returns stable 64-bit hash of IRI. It is FNV-1a over segments, each
+segment is terminated by zero byte. The hash is deterministic across runs
+and processes but it is not cryptographic.
+*/
+func (iri IRI) Hash() uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for _, x := range iri.seq() {
+		for i := 0; i < len(x); i++ {
+			h ^= uint64(x[i])
+			h *= prime64
+		}
+		// zero byte terminates the segment, xor with zero is no-op
+		h *= prime64
 	}
 
-	return strings.Join(iri.Seq[n:len(iri.Seq)], ":")
+	return h
 }
 
 /*
 
-Parent returns a IRI that is prefix of this one
+Len returns number of segments, the empty IRI has none
 */
-func (iri IRI) Parent(rank ...int) IRI {
-	r := 1
-	if len(rank) > 0 {
-		r = rank[0]
-	}
+func (iri IRI) Len() int {
+	return len(iri.seq())
+}
 
-	n := len(iri.Seq) - r
-	if n <= 0 {
-		return IRI{Seq: []string{""}}
-	}
+/*
 
-	return IRI{Seq: append([]string{}, iri.Seq[:n]...)}
+Segments return elements, the slice is a copy of IRI internal state
+*/
+func (iri IRI) Segments() []string {
+	return append([]string{}, iri.Seq...)
 }
 
 /*
 
-Heir returns a IRI that descendant of this one.
+Segment returns element at index i, false if index is out of range
 */
-func (iri IRI) Heir(segment string) IRI {
-	if len(iri.Seq) == 1 && iri.Seq[0] == "" {
-		return IRI{Seq: []string{segment}}
+func (iri IRI) Segment(i int) (string, bool) {
+	seq := iri.seq()
+	if i < 0 || i >= len(seq) {
+		return "", false
 	}
 
-	return IRI{Seq: append(append([]string{}, iri.Seq...), segment)}
+	return seq[i], true
 }
 
 /*
 
-String ...
+MarshalJSON `IRI ⟼ "prefix:suffix"`
 */
-func (iri IRI) String() string {
-	return strings.Join(iri.Seq, ":")
+func (iri IRI) MarshalJSON() ([]byte, error) {
+	if len(iri.Seq) == 0 {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(iri.String())
 }
 
 /*
 
-Eq return true if two IRI equals
+UnmarshalJSON `"prefix:suffix" ⟼ IRI`, JSON null is the empty IRI
 */
-func (iri IRI) Eq(x IRI) bool {
-	if len(iri.Seq) != len(x.Seq) {
-		return false
+func (iri *IRI) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*iri = NewIRI("")
+		return nil
 	}
 
-	for i, v := range iri.Seq {
-		if x.Seq[i] != v {
-			return false
-		}
+	var path string
+	err := json.Unmarshal(b, &path)
+	if err != nil {
+		return fmt.Errorf("iri: expected JSON string, got %s: %w", jsonKind(b), err)
 	}
 
-	return true
+	if err := checkSegments(path); err != nil {
+		return err
+	}
+
+	*iri = parse(path)
+	return nil
+}
+
+// jsonKind names type of JSON value for diagnostic
+func jsonKind(b []byte) string {
+	if len(b) == 0 {
+		return "nothing"
+	}
+
+	switch b[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "boolean"
+	default:
+		return "number"
+	}
 }
 
 /*
 
-Segments return elements
+MarshalText `IRI ⟼ "prefix:suffix"`
+
+Note: the method is defined on IRI only. Defined on ID, it would be promoted
+to structs that embed ID and override their encoding, e.g. encoding/json
+would encode such struct as bare IRI string. Use IRI where
+encoding.TextMarshaler is required.
 */
-func (iri IRI) Segments() []string {
-	return iri.Seq
+func (iri IRI) MarshalText() ([]byte, error) {
+	return []byte(iri.String()), nil
 }
 
 /*
 
-MarshalJSON `IRI ⟼ "prefix:suffix"`
+UnmarshalText `"prefix:suffix" ⟼ IRI`
 */
-func (iri IRI) MarshalJSON() ([]byte, error) {
-	if len(iri.Seq) == 0 {
-		return json.Marshal("")
+func (iri *IRI) UnmarshalText(b []byte) error {
+	*iri = parse(string(b))
+	return nil
+}
+
+/*
+
+MarshalYAML `IRI ⟼ prefix:suffix`
+
+ID is not yaml.Marshaler for the reason given at MarshalText, the field of
+type ID is encoded as nested mapping `key: {id: prefix:suffix}`. Use the field
+of type IRI or embed ID with `yaml:",inline"` to get `key: prefix:suffix`.
+*/
+func (iri IRI) MarshalYAML() (interface{}, error) {
+	return iri.String(), nil
+}
+
+/*
+
+UnmarshalYAML `prefix:suffix ⟼ IRI`
+*/
+func (iri *IRI) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err != nil {
+		return err
 	}
 
-	return json.Marshal(iri.String())
+	*iri = parse(path)
+	return nil
 }
 
 /*
 
-UnmarshalJSON `"prefix:suffix" ⟼ IRI`
+MarshalXML `IRI ⟼ <id>prefix:suffix</id>`
 */
-func (iri *IRI) UnmarshalJSON(b []byte) error {
+func (iri IRI) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(iri.String(), start)
+}
+
+/*
+
+UnmarshalXML `<id>prefix:suffix</id> ⟼ IRI`
+*/
+func (iri *IRI) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var path string
-	err := json.Unmarshal(b, &path)
-	if err != nil {
+	if err := d.DecodeElement(&path, &start); err != nil {
 		return err
 	}
 
-	*iri = New(path).IRI
+	*iri = parse(path)
+	return nil
+}
+
+/*
+
+GobEncode `IRI ⟼ "prefix:suffix"`
+*/
+func (iri IRI) GobEncode() ([]byte, error) {
+	return []byte(iri.String()), nil
+}
+
+/*
+
+GobDecode `"prefix:suffix" ⟼ IRI`
+*/
+func (iri *IRI) GobDecode(b []byte) error {
+	*iri = parse(string(b))
 	return nil
 }
 
@@ -293,6 +1867,195 @@ func (iri IRI) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error
 UnmarshalDynamoDBAttributeValue `"prefix/suffix" ⟼ IRI`
 */
 func (iri *IRI) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
-	*iri = NewIRI(aws.StringValue(av.S))
+	val := aws.StringValue(av.S)
+	if err := checkSegments(val); err != nil {
+		return err
+	}
+
+	*iri = parse(val)
+	return nil
+}
+
+/*
+
+Value implements driver.Valuer `IRI ⟼ "prefix:suffix"`, the empty IRI is NULL
+*/
+func (iri IRI) Value() (driver.Value, error) {
+	if iri.IsEmpty() {
+		return nil, nil
+	}
+
+	return iri.String(), nil
+}
+
+/*
+
+Scan implements sql.Scanner `"prefix:suffix" ⟼ IRI`, NULL is the empty IRI
+*/
+func (iri *IRI) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*iri = NewIRI("")
+	case string:
+		*iri = parse(v)
+	case []byte:
+		*iri = parse(string(v))
+	default:
+		return fmt.Errorf("iri: unsupported scan type %T", src)
+	}
+
+	return nil
+}
+
+// rankOf returns optional rank counted from the leaf, default is 1.
+// The negative rank counts from the root, it is clamped to length of IRI.
+func rankOf(seq []string, rank []int) int {
+	if len(rank) == 0 {
+		return 1
+	}
+
+	r := rank[0]
+	if r < 0 {
+		r = len(seq) + r
+		if r < 0 {
+			r = 0
+		}
+	}
+
+	return r
+}
+
+var unescaper = strings.NewReplacer("%25", "%", "%23", "#", "%3A", ":", "%3a", ":")
+
+// shortIDEncoding is lower case base32 without padding, see ShortID
+var shortIDEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// filePathEscaper neutralizes path separators within segments
+var filePathEscaper = strings.NewReplacer("%", "%25", "/", "%2F", "\\", "%5C", ":", "%3A")
+
+// join segments into compact IRI, escaping separator within segments
+func join(seq []string) string {
+	size, escaped := 0, false
+	for _, x := range seq {
+		size += len(x) + 1
+		escaped = escaped || needsEscape(x)
+	}
+
+	if !escaped {
+		return strings.Join(seq, ":")
+	}
+
+	var sb strings.Builder
+	sb.Grow(size + 8)
+	for i, x := range seq {
+		if i > 0 {
+			sb.WriteByte(':')
+		}
+		for j := 0; j < len(x); j++ {
+			if esc := escapeOf(x, j); esc != "" {
+				sb.WriteString(esc)
+				continue
+			}
+			sb.WriteByte(x[j])
+		}
+	}
+
+	return sb.String()
+}
+
+// needsEscape returns true if segment has bytes to escape
+func needsEscape(segment string) bool {
+	if !strings.ContainsAny(segment, "%:") {
+		return false
+	}
+
+	for i := 0; i < len(segment); i++ {
+		if escapeOf(segment, i) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapeOf returns escape sequence for byte at position i of segment or
+// empty string if byte is kept verbatim. The separator is always escaped,
+// "%" is escaped only if it starts an escape sequence, a lone "%" is kept.
+func escapeOf(segment string, i int) string {
+	switch segment[i] {
+	case ':':
+		return "%3A"
+	case '%':
+		if isEscape(segment[i:]) {
+			return "%25"
+		}
+	}
+
+	return ""
+}
+
+// isEscape returns true if s starts with escape sequence decoded by unescape
+func isEscape(s string) bool {
+	return strings.HasPrefix(s, "%25") || strings.HasPrefix(s, "%23") ||
+		strings.HasPrefix(s, "%3A") || strings.HasPrefix(s, "%3a")
+}
+
+// parse builds IRI from string without formatting
+func parse(s string) IRI {
+	return IRI{Seq: split(s)}
+}
+
+// split compact IRI into segments, unescaping separator within segments
+func split(s string) []string {
+	seq := strings.Split(s, ":")
+	for i, x := range seq {
+		seq[i] = unescape(x)
+	}
+
+	return seq
+}
+
+func unescape(segment string) string {
+	if !strings.Contains(segment, "%") {
+		return segment
+	}
+
+	return unescaper.Replace(segment)
+}
+
+// checkSegments enforces MaxSegments on compact IRI before it is split
+func checkSegments(s string) error {
+	return checkDepth(strings.Count(s, ":") + 1)
+}
+
+func checkDepth(n int) error {
+	if MaxSegments > 0 && n > MaxSegments {
+		return fmt.Errorf("iri: %d segments exceed limit of %d", n, MaxSegments)
+	}
+
 	return nil
 }
+
+// seq returns meaningful segments of IRI, the empty IRI has none
+func (iri IRI) seq() []string {
+	if len(iri.Seq) == 1 && iri.Seq[0] == "" {
+		return nil
+	}
+
+	return iri.Seq
+}
+
+// hasPrefix returns true if sequence starts with prefix segments
+func hasPrefix(seq, prefix []string) bool {
+	return len(prefix) <= len(seq) && commonPrefixLen(seq, prefix) == len(prefix)
+}
+
+// commonPrefixLen returns number of leading segments shared by sequences
+func commonPrefixLen(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+
+	return n
+}
@@ -1,12 +1,23 @@
 package iri_test
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/gob"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/fogfish/iri"
 	"github.com/fogfish/it"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -35,6 +46,273 @@ func TestIRI(t *testing.T) {
 	}
 }
 
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"", "a", "a:b:c", "a::b", ":a", "a:", " ", "a:12%3A00", "%25", "%", "%%3A"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		id, err := iri.Parse(s)
+		if err != nil {
+			return
+		}
+
+		rt, err := iri.Parse(id.String())
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(rt).Should().Equal(id).
+			If(rt.String()).Should().Equal(id.String())
+	})
+}
+
+func FuzzEscape(f *testing.F) {
+	for _, seed := range [][2]string{{"a", "b"}, {"12:00", "100%"}, {"%25", "%3a"}, {"%:", "%%3A"}, {"a#b", "%23"}, {"", "%"}} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		id := iri.Join(a, b)
+
+		it.Ok(t).
+			If(iri.New("%s", id.String())).Should().Equal(id)
+
+		if !strings.Contains(a+b, ":") && !strings.Contains(a+b, "%25") &&
+			!strings.Contains(a+b, "%23") && !strings.Contains(a+b, "%3A") &&
+			!strings.Contains(a+b, "%3a") {
+			it.Ok(t).If(id.String()).Should().Equal(a + ":" + b)
+		}
+	})
+}
+
+func TestMustParse(t *testing.T) {
+	it.Ok(t).
+		If(iri.MustParse("")).Should().Equal(r0).
+		If(iri.MustParse("a:b:c")).Should().Equal(r3)
+}
+
+func TestMustParsePanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		it.Ok(t).
+			If(r).Should().Equal("iri: MustParse(\"a::b\"): iri: segment 1 is empty")
+	}()
+
+	iri.MustParse("a::b")
+}
+
+func TestNewWithSep(t *testing.T) {
+	it.Ok(t).
+		If(iri.NewWithSep("/", "")).Should().Equal(r0).
+		If(iri.NewWithSep("/", "a")).Should().Equal(r1).
+		If(iri.NewWithSep("/", "a/b/c")).Should().Equal(r3).
+		If(iri.NewWithSep(".", "a.b.c.d")).Should().Equal(r4).
+		If(iri.NewWithSep("::", "a::b")).Should().Equal(r2).
+		If(iri.NewWithSep("/", "a.b")).Should().Equal(iri.Join("a.b")).
+		If(iri.NewWithSep("/", "a:b/c")).Should().Equal(iri.Join("a:b", "c")).
+		If(iri.NewWithSep("", "a/b")).Should().Equal(iri.Join("a/b"))
+}
+
+func TestNewTrim(t *testing.T) {
+	it.Ok(t).
+		If(iri.NewTrim(" a : b ")).Should().Equal(r2).
+		If(iri.NewTrim(" a : b ").Segments()).Should().Equal([]string{"a", "b"}).
+		If(iri.NewTrim("\ta:b:c\n")).Should().Equal(r3).
+		If(iri.NewTrim("a:b")).Should().Equal(r2).
+		If(iri.NewTrim("  ")).Should().Equal(r0).
+		If(iri.NewTrim("")).Should().Equal(r0).
+		If(iri.NewTrim(" a : 12%3A00 ")).Should().Equal(iri.Join("a", "12:00")).
+		If(iri.New(" a : b ").Segments()).Should().Equal([]string{" a ", " b "})
+}
+
+func TestParse(t *testing.T) {
+	for _, eg := range []iri.ID{r0, r1, r2, r3, r4, r5} {
+		id, err := iri.Parse(eg.IRI.String())
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(id).Should().Equal(eg)
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	test := map[string]string{
+		"a::b":  "iri: segment 1 is empty",
+		":a":    "iri: segment 0 is empty",
+		"a:":    "iri: segment 1 is empty",
+		":":     "iri: segment 0 is empty",
+		"a: :b": "iri: segment 1 is blank",
+		" ":     "iri: segment 0 is blank",
+	}
+
+	for eg, expect := range test {
+		_, err := iri.Parse(eg)
+		it.Ok(t).
+			If(err).ShouldNot().Equal(nil).
+			If(err.Error()).Should().Equal(expect)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	seq := []string{"user", "2023:01", "42"}
+	id := iri.Join(seq...)
+	seq[0] = "x"
+
+	it.Ok(t).
+		If(iri.Join()).Should().Equal(r0).
+		If(iri.Join("a", "b", "c")).Should().Equal(r3).
+		If(id.Segments()).Should().Equal([]string{"user", "2023:01", "42"}).
+		If(id.Len()).Should().Equal(3)
+}
+
+func TestFromSegments(t *testing.T) {
+	seq := []string{"a", "b", "c"}
+	id := iri.FromSegments(seq)
+
+	it.Ok(t).
+		If(id).Should().Equal(r3).
+		If(iri.FromSegments(nil)).Should().Equal(r0)
+
+	seq[0] = "x"
+	seq = append(seq[:1], "y")
+
+	it.Ok(t).
+		If(id).Should().Equal(r3).
+		If(id.Segments()).Should().Equal([]string{"a", "b", "c"})
+}
+
+func TestSegment(t *testing.T) {
+	test := map[int]string{0: "a", 1: "b", 2: "c"}
+
+	for i, expect := range test {
+		seg, ok := r3.Segment(i)
+		it.Ok(t).
+			If(ok).Should().Equal(true).
+			If(seg).Should().Equal(expect)
+	}
+
+	for _, i := range []int{-1, 3} {
+		_, ok := r3.Segment(i)
+		it.Ok(t).If(ok).Should().Equal(false)
+	}
+
+	_, ok := r0.Segment(0)
+	it.Ok(t).If(ok).Should().Equal(false)
+}
+
+func TestSegmentsImmutable(t *testing.T) {
+	id := iri.New("a:b:c")
+	seq := id.Segments()
+	seq[0] = "x"
+
+	seg, _ := id.Segment(0)
+
+	it.Ok(t).
+		If(id).Should().Equal(r3).
+		If(seg).Should().Equal("a").
+		If(id.Segments()).Should().Equal([]string{"a", "b", "c"})
+}
+
+func TestLongestValid(t *testing.T) {
+	test := map[string][2]string{
+		"":       {"", ""},
+		"a:b:c":  {"a:b:c", ""},
+		"a:b::c": {"a:b", "::c"},
+		"a:b:":   {"a:b", ":"},
+		":a:b":   {"", ":a:b"},
+		"a: :b":  {"a", ": :b"},
+	}
+
+	for k, v := range test {
+		id, rest := iri.LongestValid(k)
+		it.Ok(t).
+			If(id).Should().Equal(iri.New("%s", v[0])).
+			If(rest).Should().Equal(v[1])
+	}
+}
+
+func TestFromColumns(t *testing.T) {
+	seq := []string{"a", "b", "c"}
+	id, err := iri.FromColumns(3, seq)
+	seq[0] = "x"
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(id).Should().Equal(r3)
+
+	id, err = iri.FromColumns(0, nil)
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(id).Should().Equal(r0)
+
+	_, err = iri.FromColumns(2, []string{"a", "b", "c"})
+	it.Ok(t).
+		If(err).ShouldNot().Equal(nil)
+}
+
+func TestIsEmpty(t *testing.T) {
+	it.Ok(t).
+		If(iri.New("").IsEmpty()).Should().Equal(true).
+		If(iri.New("").IsRoot()).Should().Equal(true).
+		If(iri.Join().IsEmpty()).Should().Equal(true).
+		If(iri.ID{}.IsEmpty()).Should().Equal(true).
+		If(iri.New("a").IsEmpty()).Should().Equal(false).
+		If(iri.New("a").IsRoot()).Should().Equal(false).
+		If(iri.Join("a").IsEmpty()).Should().Equal(false).
+		If(r3.Parent().Parent().IsEmpty()).Should().Equal(false).
+		If(r3.Parent().Parent().Parent().IsEmpty()).Should().Equal(true).
+		If(r3.Parent().Parent().Parent().Parent().IsRoot()).Should().Equal(true)
+}
+
+func TestHash(t *testing.T) {
+	it.Ok(t).
+		If(r2.Hash()).Should().Equal(iri.New("a:b").Hash()).
+		If(r2.Hash()).Should().Equal(iri.Join("a", "b").Hash()).
+		If(r2.Hash()).Should().Equal(r2.IRI.Hash()).
+		If(r2.Hash()).Should().Equal(uint64(0xab40d7820d408076)).
+		If(r2.Hash()).ShouldNot().Equal(iri.New("ab").Hash()).
+		If(r0.Hash()).ShouldNot().Equal(iri.New("a:").Hash())
+}
+
+func TestShortID(t *testing.T) {
+	it.Ok(t).
+		If(r2.ShortID()).Should().Equal("vnanpaqn").
+		If(r2.ShortID()).Should().Equal(iri.Join("a", "b").ShortID()).
+		If(len(r5.ShortID())).Should().Equal(8).
+		If(len(r0.ShortID())).Should().Equal(8).
+		If(r2.ShortID()).ShouldNot().Equal(r3.ShortID())
+
+	seen := map[string]string{}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			id := iri.New("t%d:r%d", i, j)
+			_, exists := seen[id.ShortID()]
+			it.Ok(t).If(exists).Should().Equal(false)
+			seen[id.ShortID()] = id.String()
+		}
+	}
+}
+
+func TestHashCollision(t *testing.T) {
+	seen := map[uint64]string{}
+
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			id := iri.New("t%d:r%d", i, j)
+			_, exists := seen[id.Hash()]
+			it.Ok(t).If(exists).Should().Equal(false)
+			seen[id.Hash()] = id.IRI.String()
+		}
+	}
+}
+
+func TestLen(t *testing.T) {
+	it.Ok(t).
+		If(iri.New("").Len()).Should().Equal(0).
+		If(iri.New("a").Len()).Should().Equal(1).
+		If(r5.Len()).Should().Equal(5).
+		If(r5.IRI.Len()).Should().Equal(5).
+		If(r1.Parent().Len()).Should().Equal(0)
+}
+
 func TestPrefix(t *testing.T) {
 	test := map[*iri.ID][]string{
 		&r0: {"", "", "", "", "", ""},
@@ -77,6 +355,57 @@ func TestSuffix(t *testing.T) {
 	}
 }
 
+func TestRankFromRoot(t *testing.T) {
+	it.Ok(t).
+		If(r5.Prefix(-1)).Should().Equal("a").
+		If(r5.Prefix(-2)).Should().Equal("a:b").
+		If(r5.Prefix(-5)).Should().Equal("a:b:c:d:e").
+		If(r5.Prefix(-9)).Should().Equal("a:b:c:d:e").
+		If(r5.Suffix(-1)).Should().Equal("b:c:d:e").
+		If(r5.Suffix(-4)).Should().Equal("e").
+		If(r5.Suffix(-5)).Should().Equal("").
+		If(r5.Suffix(-9)).Should().Equal("").
+		If(r5.Parent(-2)).Should().Equal(r2).
+		If(r5.Parent(-4)).Should().Equal(r4).
+		If(r5.Parent(-9)).Should().Equal(r5).
+		If(r1.Prefix(-1)).Should().Equal("a").
+		If(r1.Suffix(-1)).Should().Equal("").
+		If(r0.Prefix(-1)).Should().Equal("").
+		If(r0.Suffix(-1)).Should().Equal("").
+		If(r0.Parent(-1)).Should().Equal(r0)
+
+	for i := 1; i < r5.Len(); i++ {
+		it.Ok(t).
+			If(r5.Prefix(-i)).Should().Equal(r5.Prefix(r5.Len() - i)).
+			If(r5.Suffix(-i)).Should().Equal(r5.Suffix(r5.Len() - i)).
+			If(r5.Parent(-i)).Should().Equal(r5.Parent(r5.Len() - i))
+	}
+}
+
+func TestPartitionSortKey(t *testing.T) {
+	test := map[*iri.ID][2]string{
+		&r0: {"", ""},
+		&r1: {"a", ""},
+		&r2: {"a", "b"},
+		&r3: {"a:b", "c"},
+		&r5: {"a:b:c:d", "e"},
+	}
+
+	for k, v := range test {
+		it.Ok(t).
+			If(k.PartitionKey()).Should().Equal(v[0]).
+			If(k.SortKey()).Should().Equal(v[1])
+	}
+
+	for _, id := range []iri.ID{r1, r2, r3} {
+		av, err := dynamodbattribute.Marshal(iri.SortKeyID{ID: id})
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(*av.M["pk"].S).Should().Equal(id.PartitionKey()).
+			If(*av.M["sk"].S).Should().Equal(id.SortKey())
+	}
+}
+
 func TestParent(t *testing.T) {
 	test := map[*iri.ID][]iri.ID{
 		&r0: {r0, r0, r0, r0, r0, r0},
@@ -98,6 +427,152 @@ func TestParent(t *testing.T) {
 	}
 }
 
+func TestScheme(t *testing.T) {
+	it.Ok(t).
+		If(r0.Scheme()).Should().Equal("").
+		If(r1.Scheme()).Should().Equal("a").
+		If(r3.Scheme()).Should().Equal("a")
+}
+
+func TestWithScheme(t *testing.T) {
+	it.Ok(t).
+		If(r3.WithScheme("x")).Should().Equal(iri.New("x:b:c")).
+		If(r1.WithScheme("x")).Should().Equal(iri.New("x")).
+		If(r0.WithScheme("x")).Should().Equal(iri.New("x")).
+		If(r3).Should().Equal(iri.New("a:b:c"))
+}
+
+func TestIsAbsolute(t *testing.T) {
+	urn := iri.New("urn:isbn:1")
+
+	it.Ok(t).
+		If(urn.IsAbsolute("urn", "http")).Should().Equal(true).
+		If(urn.IsRelative("urn", "http")).Should().Equal(false).
+		If(r3.IsAbsolute("urn", "http")).Should().Equal(false).
+		If(r3.IsRelative("urn", "http")).Should().Equal(true).
+		If(r0.IsAbsolute("urn", "")).Should().Equal(true).
+		If(r0.IsAbsolute("urn")).Should().Equal(false).
+		If(urn.IsAbsolute()).Should().Equal(true).
+		If(r1.IsAbsolute()).Should().Equal(true).
+		If(iri.New(":a").IsAbsolute()).Should().Equal(false).
+		If(r0.IsAbsolute()).Should().Equal(false).
+		If(r0.IsRelative()).Should().Equal(true)
+}
+
+func TestLocalName(t *testing.T) {
+	it.Ok(t).
+		If(r0.LocalName()).Should().Equal("").
+		If(r1.LocalName()).Should().Equal("a").
+		If(r3.LocalName()).Should().Equal("c").
+		If(iri.Join("a", "12:00").LocalName()).Should().Equal("12:00")
+}
+
+func TestNamespace(t *testing.T) {
+	it.Ok(t).
+		If(r0.Namespace()).Should().Equal(r0).
+		If(r1.Namespace()).Should().Equal(r0).
+		If(r3.Namespace()).Should().Equal(r2).
+		If(r3.Namespace()).Should().Equal(r3.Parent())
+}
+
+func TestTruncate(t *testing.T) {
+	it.Ok(t).
+		If(r4.Truncate(2)).Should().Equal(r2).
+		If(r4.Truncate(1)).Should().Equal(r1).
+		If(r4.Truncate(0)).Should().Equal(r0).
+		If(r4.Truncate(-1)).Should().Equal(r0).
+		If(r4.Truncate(4)).Should().Equal(r4).
+		If(r4.Truncate(10)).Should().Equal(r4).
+		If(r0.Truncate(2)).Should().Equal(r0).
+		If(r4).Should().Equal(iri.New("a:b:c:d"))
+}
+
+func TestSplitAt(t *testing.T) {
+	test := map[int][2]iri.ID{
+		-1: {r0, r4},
+		0:  {r0, r4},
+		1:  {r1, iri.New("b:c:d")},
+		2:  {r2, iri.New("c:d")},
+		3:  {r3, iri.New("d")},
+		4:  {r4, r0},
+		5:  {r4, r0},
+	}
+
+	for rank, expect := range test {
+		head, tail := r4.SplitAt(rank)
+		it.Ok(t).
+			If(head).Should().Equal(expect[0]).
+			If(tail).Should().Equal(expect[1])
+	}
+
+	head, tail := r0.SplitAt(1)
+	it.Ok(t).
+		If(head).Should().Equal(r0).
+		If(tail).Should().Equal(r0)
+}
+
+func TestAncestors(t *testing.T) {
+	it.Ok(t).
+		If(r0.Ancestors()).Should().Equal([]iri.ID{}).
+		If(r1.Ancestors()).Should().Equal([]iri.ID{}).
+		If(r2.Ancestors()).Should().Equal([]iri.ID{r1}).
+		If(r5.Ancestors()).Should().Equal([]iri.ID{r1, r2, r3, r4})
+}
+
+func TestLineage(t *testing.T) {
+	it.Ok(t).
+		If(r0.Lineage()).Should().Equal([]iri.ID{}).
+		If(r1.Lineage()).Should().Equal([]iri.ID{r1}).
+		If(r3.Lineage()).Should().Equal([]iri.ID{r1, r2, r3})
+}
+
+func TestPrefixes(t *testing.T) {
+	it.Ok(t).
+		If(r0.Prefixes()).Should().Equal([]string{}).
+		If(r1.Prefixes()).Should().Equal([]string{"a"}).
+		If(r3.Prefixes()).Should().Equal([]string{"a", "a:b", "a:b:c"}).
+		If(iri.Join("a", "b:c").Prefixes()).Should().Equal([]string{"a", "a:b%3Ac"})
+}
+
+func TestSuffixes(t *testing.T) {
+	it.Ok(t).
+		If(r0.Suffixes()).Should().Equal([]string{}).
+		If(r1.Suffixes()).Should().Equal([]string{"a"}).
+		If(r3.Suffixes()).Should().Equal([]string{"c", "b:c", "a:b:c"}).
+		If(iri.Join("a", "b:c").Suffixes()).Should().Equal([]string{"b%3Ac", "a:b%3Ac"})
+}
+
+func TestWalk(t *testing.T) {
+	seq := []iri.ID{}
+	r3.Walk(func(prefix iri.ID) bool {
+		seq = append(seq, prefix)
+		return true
+	})
+
+	it.Ok(t).
+		If(seq).Should().Equal([]iri.ID{r1, r2, r3})
+
+	seq = []iri.ID{}
+	r0.Walk(func(prefix iri.ID) bool {
+		seq = append(seq, prefix)
+		return true
+	})
+
+	it.Ok(t).
+		If(seq).Should().Equal([]iri.ID{})
+}
+
+func TestWalkStop(t *testing.T) {
+	seq := []iri.ID{}
+	r5.Walk(func(prefix iri.ID) bool {
+		seq = append(seq, prefix)
+		return prefix.Len() < 2
+	})
+
+	it.Ok(t).
+		If(seq).Should().Equal([]iri.ID{r1, r2})
+}
+
 func TestHeir(t *testing.T) {
 	it.Ok(t).
 		If(r0.Heir("a")).Should().Equal(r1).
@@ -107,6 +582,175 @@ func TestHeir(t *testing.T) {
 		If(r4.Heir("e")).Should().Equal(r5)
 }
 
+func TestHeirs(t *testing.T) {
+	it.Ok(t).
+		If(r0.Heirs()).Should().Equal(r0).
+		If(r0.Heirs("a", "b")).Should().Equal(r2).
+		If(r0.Heirs("a", "b", "c", "d", "e")).Should().Equal(r5).
+		If(r1.Heirs()).Should().Equal(r1).
+		If(r1.Heirs("b", "c", "d")).Should().Equal(r4).
+		If(r2.Heirs("c").Heirs("d", "e")).Should().Equal(r5)
+}
+
+func TestImmutableHeirs(t *testing.T) {
+	rA := r2.Heirs("x", "y")
+	rB := r2.Heirs("t")
+
+	it.Ok(t).
+		If(r2.Path()).Should().Equal("a/b").
+		If(rA.Path()).Should().Equal("a/b/x/y").
+		If(rB.Path()).Should().Equal("a/b/t")
+}
+
+func TestSetSegment(t *testing.T) {
+	id := iri.New("tenant:acme:order:1")
+
+	it.Ok(t).
+		If(id.SetSegment(0, "t")).Should().Equal(iri.New("t:acme:order:1")).
+		If(id.SetSegment(1, "globex")).Should().Equal(iri.New("tenant:globex:order:1")).
+		If(id.SetSegment(3, "2")).Should().Equal(iri.New("tenant:acme:order:2")).
+		If(id.SetSegment(4, "x")).Should().Equal(id).
+		If(id.SetSegment(-1, "x")).Should().Equal(id).
+		If(r0.SetSegment(0, "x")).Should().Equal(r0).
+		If(id).Should().Equal(iri.New("tenant:acme:order:1"))
+}
+
+func TestReverse(t *testing.T) {
+	it.Ok(t).
+		If(r0.Reverse()).Should().Equal(r0).
+		If(r1.Reverse()).Should().Equal(r1).
+		If(r4.Reverse()).Should().Equal(iri.New("d:c:b:a")).
+		If(r5.Reverse()).Should().Equal(iri.New("e:d:c:b:a")).
+		If(r4).Should().Equal(iri.New("a:b:c:d"))
+
+	for _, eg := range []iri.ID{r0, r1, r2, r3, r4, r5} {
+		it.Ok(t).If(eg.Reverse().Reverse()).Should().Equal(eg)
+	}
+}
+
+func TestWithSuffix(t *testing.T) {
+	it.Ok(t).
+		If(r3.WithSuffix(1, "x", "y")).Should().Equal(iri.New("a:b:x:y")).
+		If(r3.WithSuffix(2, "x")).Should().Equal(iri.New("a:x")).
+		If(r3.WithSuffix(0, "x")).Should().Equal(iri.New("a:b:c:x")).
+		If(r3.WithSuffix(1)).Should().Equal(r2).
+		If(r3.WithSuffix(3, "x", "y")).Should().Equal(iri.New("x:y")).
+		If(r3.WithSuffix(9, "x", "y")).Should().Equal(iri.New("x:y")).
+		If(r3.WithSuffix(9)).Should().Equal(r0).
+		If(r0.WithSuffix(1, "x")).Should().Equal(iri.New("x")).
+		If(r3).Should().Equal(iri.New("a:b:c"))
+}
+
+func TestAppend(t *testing.T) {
+	it.Ok(t).
+		If(r0.Append(r0)).Should().Equal(r0).
+		If(r0.Append(r2)).Should().Equal(r2).
+		If(r2.Append(r0)).Should().Equal(r2).
+		If(r2.Append(iri.New("c:d"))).Should().Equal(r4).
+		If(r2).Should().Equal(iri.New("a:b"))
+}
+
+func TestCons(t *testing.T) {
+	it.Ok(t).
+		If(r0.Cons("a")).Should().Equal(r1).
+		If(iri.New("b").Cons("a")).Should().Equal(r2).
+		If(iri.New("b:c").Cons("a")).Should().Equal(r3).
+		If(iri.New("b:c:d").Cons("a")).Should().Equal(r4).
+		If(iri.New("b:c:d:e").Cons("a")).Should().Equal(r5)
+}
+
+func TestSnoc(t *testing.T) {
+	it.Ok(t).
+		If(r0.Snoc("a")).Should().Equal(r1).
+		If(r1.Snoc("b")).Should().Equal(r2).
+		If(r2.Snoc("c")).Should().Equal(r3).
+		If(r3.Snoc("d")).Should().Equal(r4).
+		If(r4.Snoc("e")).Should().Equal(r5)
+}
+
+func TestString(t *testing.T) {
+	it.Ok(t).
+		If(fmt.Sprintf("%s", iri.New("a:b"))).Should().Equal("a:b").
+		If(fmt.Sprintf("%v", r3)).Should().Equal("a:b:c").
+		If(r0.String()).Should().Equal("")
+}
+
+func TestJoinWith(t *testing.T) {
+	it.Ok(t).
+		If(r3.JoinWith(".")).Should().Equal("a.b.c").
+		If(r3.JoinWith("-")).Should().Equal("a-b-c").
+		If(r3.JoinWith(" :: ")).Should().Equal("a :: b :: c").
+		If(r3.JoinWith(":")).Should().Equal(r3.String()).
+		If(r3.JoinWith("/")).Should().Equal(r3.Path()).
+		If(r1.JoinWith(".")).Should().Equal("a").
+		If(r0.JoinWith(".")).Should().Equal("").
+		If(iri.Join("a", "b.c").JoinWith(".")).Should().Equal("a.b.c")
+}
+
+func TestFilePath(t *testing.T) {
+	it.Ok(t).
+		If(r0.FilePath()).Should().Equal("").
+		If(r1.FilePath()).Should().Equal("a").
+		If(r3.FilePath()).Should().Equal(filepath.Join("a", "b", "c")).
+		If(iri.Join("a", "..", "b").FilePath()).Should().Equal(filepath.Join("a", "%2E%2E", "b")).
+		If(iri.Join("..", "..", "etc").FilePath()).Should().Equal(filepath.Join("%2E%2E", "%2E%2E", "etc")).
+		If(iri.Join("a", ".", "b").FilePath()).Should().Equal(filepath.Join("a", "%2E", "b")).
+		If(iri.Join("a", "b/../../c").FilePath()).Should().Equal(filepath.Join("a", "b%2F..%2F..%2Fc")).
+		If(iri.Join("a", `b\c`).FilePath()).Should().Equal(filepath.Join("a", "b%5Cc")).
+		If(iri.Join("/a", "b").FilePath()).Should().Equal(filepath.Join("%2Fa", "b")).
+		If(iri.Join("C:", "Windows").FilePath()).Should().Equal(filepath.Join("C%3A", "Windows")).
+		If(iri.New("%s", "C%3A:Windows").FilePath()).Should().Equal(filepath.Join("C%3A", "Windows")).
+		If(iri.Join("a", "b:c").FilePath()).Should().Equal(filepath.Join("a", "b%3Ac"))
+
+	for _, id := range []iri.ID{
+		r1, r5,
+		iri.Join("..", "..", "etc"),
+		iri.Join("a", "b/../../c"),
+		iri.Join("/a", `\b`),
+		iri.Join("C:", "Windows"),
+		iri.Join("C:..", "x"),
+	} {
+		it.Ok(t).If(filepath.IsLocal(id.FilePath())).Should().Equal(true)
+	}
+}
+
+func TestChildRange(t *testing.T) {
+	lo, hi := r2.ChildRange()
+	within := func(id iri.ID) bool {
+		s := id.String()
+		return lo <= s && s < hi
+	}
+
+	it.Ok(t).
+		If(lo).Should().Equal("a:b:").
+		If(hi).Should().Equal("a:b;").
+		If(within(r3)).Should().Equal(true).
+		If(within(r5)).Should().Equal(true).
+		If(within(iri.New("a:b:"))).Should().Equal(true).
+		If(within(r2)).Should().Equal(false).
+		If(within(iri.New("a:bc"))).Should().Equal(false).
+		If(within(iri.New("a:c"))).Should().Equal(false).
+		If(within(iri.New("a:b;c"))).Should().Equal(false).
+		If(within(iri.Join("a", "b:c"))).Should().Equal(false)
+
+	lo, hi = r0.ChildRange()
+	it.Ok(t).
+		If(lo).Should().Equal("").
+		If(hi).Should().Equal("")
+}
+
+func TestWriteTo(t *testing.T) {
+	for _, id := range []iri.ID{r0, r1, r5, iri.Join("a", "12:00", "100%"), iri.New("a::b")} {
+		var buf bytes.Buffer
+		n, err := id.WriteTo(&buf)
+
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(n).Should().Equal(int64(len(id.String()))).
+			If(buf.String()).Should().Equal(id.String())
+	}
+}
+
 func TestPath(t *testing.T) {
 	test := map[*iri.ID]string{
 		&r0: "",
@@ -131,52 +775,755 @@ func TestImmutable(t *testing.T) {
 		If(rN.Path()).Should().Equal("a/b/t")
 }
 
-func TestEq(t *testing.T) {
-	test := []iri.ID{r0, r1, r2, r3, r4, r5}
+func TestSibling(t *testing.T) {
+	it.Ok(t).
+		If(r0.Sibling("x")).Should().Equal(iri.New("x")).
+		If(r1.Sibling("x")).Should().Equal(iri.New("x")).
+		If(r2.Sibling("x")).Should().Equal(iri.New("a:x")).
+		If(r5.Sibling("x")).Should().Equal(iri.New("a:b:c:d:x")).
+		If(r3.Sibling("c")).Should().Equal(r3)
+}
 
-	for _, v := range test {
-		it.Ok(t).If(v.Eq(v)).Should().Equal(true)
-	}
+func TestImmutableSibling(t *testing.T) {
+	rS := r3.Sibling("t")
+
+	it.Ok(t).
+		If(r3.Path()).Should().Equal("a/b/c").
+		If(rS.Path()).Should().Equal("a/b/t")
 }
 
-func TestNotEq(t *testing.T) {
-	r6 := iri.New("1:2:3:4:5:6")
-	test := []iri.ID{r0, r1, r2, r3, r4, r5}
+func TestImmutableConsSnoc(t *testing.T) {
+	rH := r3.Cons("t")
+	rT := r3.Snoc("t")
 
-	for _, v := range test {
+	it.Ok(t).
+		If(r3.Path()).Should().Equal("a/b/c").
+		If(rH.Path()).Should().Equal("t/a/b/c").
+		If(rT.Path()).Should().Equal("a/b/c/t")
+}
+
+func TestCommonPrefixLen(t *testing.T) {
+	it.Ok(t).
+		If(r3.CommonPrefixLen(iri.New("a:b:d"))).Should().Equal(2).
+		If(r3.CommonPrefixLen(r3)).Should().Equal(3).
+		If(r5.CommonPrefixLen(r3)).Should().Equal(3).
+		If(r3.CommonPrefixLen(r5)).Should().Equal(3).
+		If(iri.New("x:y").CommonPrefixLen(r2)).Should().Equal(0).
+		If(iri.New("a:bc").CommonPrefixLen(r2)).Should().Equal(1).
+		If(r0.CommonPrefixLen(r3)).Should().Equal(0).
+		If(r0.CommonPrefixLen(r0)).Should().Equal(0)
+
+	for _, x := range []iri.ID{r0, r1, r2, r3, iri.New("a:x")} {
 		it.Ok(t).
-			If(v.Eq(r6)).Should().Equal(false).
-			If(v.Eq(v.Parent().Heir("t"))).Should().Equal(false)
+			If(r3.CommonPrefixLen(x)).Should().Equal(iri.CommonPrefix(r3, x).Len())
 	}
 }
 
-func TestJSON(t *testing.T) {
-	type Struct struct {
-		iri.ID
-		Title string `json:"title"`
-	}
+func TestCommonPrefix(t *testing.T) {
+	it.Ok(t).
+		If(iri.CommonPrefix(r3, iri.New("a:b:d"))).Should().Equal(r2).
+		If(iri.CommonPrefix(r5, r3)).Should().Equal(r3).
+		If(iri.CommonPrefix(r3, r5)).Should().Equal(r3).
+		If(iri.CommonPrefix(r3, r3)).Should().Equal(r3).
+		If(iri.CommonPrefix(iri.New("x"), iri.New("y"))).Should().Equal(r0).
+		If(iri.CommonPrefix(iri.New("a:bc"), r2)).Should().Equal(r1).
+		If(iri.CommonPrefix(r0, r3)).Should().Equal(r0)
+}
 
-	test := map[*Struct]string{
-		{ID: iri.New(""), Title: "t"}:      "{\"id\":\"\",\"title\":\"t\"}",
-		{ID: iri.New("a"), Title: "t"}:     "{\"id\":\"a\",\"title\":\"t\"}",
-		{ID: iri.New("a:b"), Title: "t"}:   "{\"id\":\"a:b\",\"title\":\"t\"}",
-		{ID: iri.New("a:b:c"), Title: "t"}: "{\"id\":\"a:b:c\",\"title\":\"t\"}",
+func TestSegmentDistance(t *testing.T) {
+	seq := []iri.ID{r0, r1, r3, iri.New("a:x:c"), iri.New("c:b:a")}
+	expect := [][]int{
+		{0, 1, 3, 3, 3},
+		{1, 0, 2, 2, 2},
+		{3, 2, 0, 1, 2},
+		{3, 2, 1, 0, 3},
+		{3, 2, 2, 3, 0},
 	}
 
-	for eg, expect := range test {
-		in := Struct{}
+	for i, a := range seq {
+		for j, b := range seq {
+			it.Ok(t).
+				If(iri.SegmentDistance(a, b)).Should().Equal(expect[i][j])
+		}
+	}
+}
 
-		bytes, err1 := json.Marshal(eg)
-		err2 := json.Unmarshal(bytes, &in)
+func TestTrimPrefix(t *testing.T) {
+	test := []struct {
+		id, prefix, expect iri.ID
+		ok                 bool
+	}{
+		{r4, r2, iri.New("c:d"), true},
+		{r4, r4, r0, true},
+		{r4, r0, r4, true},
+		{r0, r0, r0, true},
+		{r4, iri.New("a:x"), r4, false},
+		{r2, r4, r2, false},
+		{r0, r1, r0, false},
+	}
 
+	for _, eg := range test {
+		id, ok := eg.id.TrimPrefix(eg.prefix)
 		it.Ok(t).
-			If(err1).Should().Equal(nil).
-			If(err2).Should().Equal(nil).
+			If(ok).Should().Equal(eg.ok).
+			If(id).Should().Equal(eg.expect)
+	}
+}
+
+func TestRel(t *testing.T) {
+	test := [][3]iri.ID{
+		{r2, r4, iri.New("c:d")},
+		{r2, r2, r0},
+		{r0, r3, r3},
+		{r0, r0, r0},
+	}
+
+	for _, eg := range test {
+		rel, err := iri.Rel(eg[0], eg[1])
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(rel).Should().Equal(eg[2])
+	}
+}
+
+func TestRelNotPrefix(t *testing.T) {
+	_, err1 := iri.Rel(r4, r2)
+	_, err2 := iri.Rel(iri.New("x:y"), r4)
+	_, err3 := iri.Rel(r2, iri.New("a:bc"))
+
+	it.Ok(t).
+		If(err1).ShouldNot().Equal(nil).
+		If(err2).ShouldNot().Equal(nil).
+		If(err3).ShouldNot().Equal(nil)
+}
+
+func TestRelTo(t *testing.T) {
+	test := map[[2]string]string{
+		{"a:b:c", "a:b:x:y"}: "..:x:y",
+		{"a:b:c", "a:b:c"}:   "",
+		{"a:b", "a:b:c:d"}:   "c:d",
+		{"a:b:c:d", "a:b"}:   "..:..",
+		{"", "a:b"}:          "a:b",
+		{"a:b", ""}:          "..:..",
+		{"x:y", "a:b"}:       "..:..:a:b",
+	}
+
+	for k, v := range test {
+		rel, err := iri.New("%s", k[0]).RelTo(iri.New("%s", k[1]))
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(rel).Should().Equal(iri.New("%s", v))
+	}
+}
+
+func TestRelToNotSupported(t *testing.T) {
+	_, err1 := iri.New("a:..:b").RelTo(r2)
+	_, err2 := r2.RelTo(iri.New("a:..:b"))
+
+	it.Ok(t).
+		If(err1).ShouldNot().Equal(nil).
+		If(err2).ShouldNot().Equal(nil)
+}
+
+func TestValidateLengths(t *testing.T) {
+	limits := []int{3, 5}
+
+	it.Ok(t).
+		If(r0.ValidateLengths(limits)).Should().Equal(nil).
+		If(r5.ValidateLengths(limits)).Should().Equal(nil).
+		If(r5.ValidateLengths(nil)).Should().Equal(nil).
+		If(iri.New("abc:abcde").ValidateLengths(limits)).Should().Equal(nil).
+		If(iri.New("abcd:abcde").ValidateLengths(limits)).ShouldNot().Equal(nil).
+		If(iri.New("abc:abcdef").ValidateLengths(limits)).ShouldNot().Equal(nil)
+}
+
+func TestValidateLengthsExtraSegments(t *testing.T) {
+	limits := []int{5, 3}
+
+	it.Ok(t).
+		If(iri.New("abcde:abc:abc:abc").ValidateLengths(limits)).Should().Equal(nil).
+		If(iri.New("abcde:abc:abc:abcd").ValidateLengths(limits)).ShouldNot().Equal(nil).
+		If(iri.New("abcde:abc:abcd:abc").ValidateLengths(limits)).ShouldNot().Equal(nil)
+}
+
+func TestEq(t *testing.T) {
+	test := []iri.ID{r0, r1, r2, r3, r4, r5}
+
+	for _, v := range test {
+		it.Ok(t).If(v.Eq(v)).Should().Equal(true)
+	}
+}
+
+func TestNotEq(t *testing.T) {
+	r6 := iri.New("1:2:3:4:5:6")
+	test := []iri.ID{r0, r1, r2, r3, r4, r5}
+
+	for _, v := range test {
+		it.Ok(t).
+			If(v.Eq(r6)).Should().Equal(false).
+			If(v.Eq(v.Parent().Heir("t"))).Should().Equal(false)
+	}
+}
+
+func TestEqRoot(t *testing.T) {
+	roots := []iri.ID{
+		r0,
+		iri.New(""),
+		iri.Join(),
+		r1.Parent(),
+		r3.Parent(3),
+		{},
+		{IRI: iri.IRI{Seq: []string{}}},
+	}
+
+	for _, a := range roots {
+		for _, b := range roots {
+			it.Ok(t).
+				If(a.Eq(b)).Should().Equal(true).
+				If(a.EqIgnoreCase(b)).Should().Equal(true).
+				If(a.EqIgnoring(nil, b)).Should().Equal(true).
+				If(a.Compare(b)).Should().Equal(0).
+				If(a.Hash()).Should().Equal(b.Hash())
+		}
+
+		it.Ok(t).
+			If(a.Eq(r1)).Should().Equal(false).
+			If(r1.Eq(a)).Should().Equal(false).
+			If(a.Eq(iri.New(":"))).Should().Equal(false)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	zero := iri.ID{}
+	join := iri.Join()
+
+	it.Ok(t).
+		If(iri.Equal(zero, join)).Should().Equal(true).
+		If(iri.Equal(r1.Parent(), zero)).Should().Equal(true).
+		If(iri.Equal(r0, iri.ID{IRI: iri.IRI{Seq: []string{}}})).Should().Equal(true).
+		If(reflect.DeepEqual(zero, join)).Should().Equal(false).
+		If(iri.Equal(r3, iri.Join("a", "b", "c"))).Should().Equal(true).
+		If(iri.Equal(r3, r2)).Should().Equal(false).
+		If(iri.Equal(r0, iri.New(":"))).Should().Equal(false)
+}
+
+func TestEqThing(t *testing.T) {
+	type User struct {
+		iri.ID
+		Name string
+	}
+
+	type Group struct {
+		iri.ID
+	}
+
+	a := User{ID: iri.New("u:1"), Name: "a"}
+	b := Group{ID: iri.New("u:1")}
+	c := User{ID: iri.New("u:2"), Name: "a"}
+	d := Group{ID: iri.New("u:2")}
+
+	it.Ok(t).
+		If(a.EqThing(b)).Should().Equal(true).
+		If(a.EqThing(c)).Should().Equal(false).
+		If(a.EqThing(nil)).Should().Equal(false).
+		If(iri.Same(a, b)).Should().Equal(true).
+		If(iri.Same(c, d)).Should().Equal(true).
+		If(iri.Same(a, d)).Should().Equal(false).
+		If(iri.Same(b, c)).Should().Equal(false).
+		If(iri.Same(a, nil)).Should().Equal(false).
+		If(iri.Same(nil, nil)).Should().Equal(false)
+}
+
+func TestEqConstantTime(t *testing.T) {
+	test := []iri.ID{
+		r0, r1, r2, r3, r4, r5,
+		iri.New("a:x"), iri.New("b"), iri.New("a::b"), iri.Join("a:b"),
+	}
+
+	for _, a := range test {
+		for _, b := range test {
+			it.Ok(t).
+				If(a.EqConstantTime(b)).Should().Equal(a.Eq(b))
+		}
+	}
+}
+
+func TestEqIgnoreCase(t *testing.T) {
+	a := iri.New("HTTP:Example:Path")
+
+	it.Ok(t).
+		If(a.Eq(iri.New("http:example:path"))).Should().Equal(false).
+		If(a.EqIgnoreCase(iri.New("http:example:path"))).Should().Equal(true).
+		If(a.EqIgnoreCase(iri.New("http:example:other"))).Should().Equal(false).
+		If(a.EqIgnoreCase(iri.New("http:example"))).Should().Equal(false).
+		If(r0.EqIgnoreCase(r0)).Should().Equal(true)
+}
+
+func TestEqFoldPrefix(t *testing.T) {
+	a := iri.New("HTTP:Example:Path")
+
+	it.Ok(t).
+		If(a.EqFoldPrefix(1, iri.New("http:Example:Path"))).Should().Equal(true).
+		If(a.EqFoldPrefix(1, iri.New("http:example:Path"))).Should().Equal(false).
+		If(a.EqFoldPrefix(2, iri.New("http:example:Path"))).Should().Equal(true).
+		If(a.EqFoldPrefix(2, iri.New("http:example:path"))).Should().Equal(false).
+		If(a.EqFoldPrefix(0, iri.New("http:Example:Path"))).Should().Equal(false).
+		If(a.EqFoldPrefix(0, a)).Should().Equal(true)
+}
+
+func TestNewIRIStrict(t *testing.T) {
+	test := map[string]iri.IRI{
+		"":     {Seq: []string{""}},
+		":":    {Seq: []string{""}},
+		"a:":   {Seq: []string{"a"}},
+		":a":   {Seq: []string{"a"}},
+		"a::b": {Seq: []string{"a", "b"}},
+		"a:b":  {Seq: []string{"a", "b"}},
+	}
+
+	for eg, expect := range test {
+		it.Ok(t).
+			If(iri.NewIRIStrict("%s", eg)).Should().Equal(expect)
+	}
+
+	it.Ok(t).
+		If(iri.NewIRI("a:").Seq).Should().Equal([]string{"a", ""}).
+		If(iri.NewIRIStrict("a:").Len()).Should().Equal(1).
+		If(iri.NewIRIStrict("%s:%s", "a", "")).Should().Equal(iri.NewIRI("a"))
+}
+
+func TestNormalize(t *testing.T) {
+	it.Ok(t).
+		If(iri.New("a:").Eq(iri.New("a"))).Should().Equal(false).
+		If(iri.New("a:").Normalize().Eq(iri.New("a"))).Should().Equal(true).
+		If(iri.New("a::b:").Normalize()).Should().Equal(r2).
+		If(iri.New(":a").Normalize()).Should().Equal(r1).
+		If(iri.New(":").Normalize()).Should().Equal(r0).
+		If(r0.Normalize()).Should().Equal(r0).
+		If(r3.Normalize()).Should().Equal(r3)
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+	composed := iri.Join("caf\u00e9", "r\u00e9sum\u00e9")
+	decomposed := iri.Join("cafe\u0301", "re\u0301sume\u0301")
+
+	it.Ok(t).
+		If(composed.Eq(decomposed)).Should().Equal(false).
+		If(composed.NormalizeUnicode().Eq(decomposed.NormalizeUnicode())).Should().Equal(true).
+		If(decomposed.NormalizeUnicode()).Should().Equal(composed).
+		If(r3.NormalizeUnicode()).Should().Equal(r3).
+		If(r0.NormalizeUnicode()).Should().Equal(r0)
+}
+
+func TestMap(t *testing.T) {
+	upper := func(i int, s string) string {
+		if i%2 == 1 {
+			return strings.ToUpper(s)
+		}
+		return s
+	}
+
+	calls := 0
+	count := func(i int, s string) string {
+		calls++
+		return s
+	}
+
+	it.Ok(t).
+		If(r5.Map(upper)).Should().Equal(iri.New("a:B:c:D:e")).
+		If(r1.Map(upper)).Should().Equal(r1).
+		If(r5).Should().Equal(iri.New("a:b:c:d:e")).
+		If(r0.Map(count)).Should().Equal(r0).
+		If(calls).Should().Equal(0)
+}
+
+func TestToLower(t *testing.T) {
+	id := iri.New("HTTP:Example:Path")
+
+	it.Ok(t).
+		If(id.ToLower()).Should().Equal(iri.New("http:example:path")).
+		If(iri.Join("ÜBER", "12:AM").ToLower()).Should().Equal(iri.Join("über", "12:am")).
+		If(r3.ToLower()).Should().Equal(r3).
+		If(r0.ToLower()).Should().Equal(r0).
+		If(id).Should().Equal(iri.New("HTTP:Example:Path"))
+}
+
+func TestEqIgnoring(t *testing.T) {
+	a := iri.New("req:a1:step:1")
+	b := iri.New("req:b7:step:1")
+	c := iri.New("req:b7:step:2")
+
+	it.Ok(t).
+		If(a.EqIgnoring([]int{1}, b)).Should().Equal(true).
+		If(a.EqIgnoring([]int{1}, c)).Should().Equal(false).
+		If(a.EqIgnoring([]int{1, 3}, c)).Should().Equal(true).
+		If(a.EqIgnoring(nil, b)).Should().Equal(false).
+		If(a.EqIgnoring(nil, a)).Should().Equal(true).
+		If(a.EqIgnoring([]int{1, 10, -1}, b)).Should().Equal(true).
+		If(a.EqIgnoring([]int{3}, a.Parent())).Should().Equal(false)
+}
+
+func TestHasPrefix(t *testing.T) {
+	it.Ok(t).
+		If(r4.HasPrefix(r2)).Should().Equal(true).
+		If(r4.HasPrefix(r4)).Should().Equal(true).
+		If(r4.HasPrefix(r0)).Should().Equal(true).
+		If(r0.HasPrefix(r0)).Should().Equal(true).
+		If(r2.HasPrefix(r4)).Should().Equal(false).
+		If(r0.HasPrefix(r1)).Should().Equal(false).
+		If(iri.New("a:bc").HasPrefix(r2)).Should().Equal(false).
+		If(iri.New("x:b").HasPrefix(r1)).Should().Equal(false)
+}
+
+func TestContains(t *testing.T) {
+	it.Ok(t).
+		If(r2.Contains(r2)).Should().Equal(false).
+		If(r2.Contains(r3)).Should().Equal(true).
+		If(r2.Contains(r4)).Should().Equal(true).
+		If(r3.Contains(r2)).Should().Equal(false).
+		If(r0.Contains(r1)).Should().Equal(true).
+		If(r0.Contains(r0)).Should().Equal(false).
+		If(r2.Contains(iri.New("a:bc:d"))).Should().Equal(false)
+}
+
+func TestIsAncestorOf(t *testing.T) {
+	it.Ok(t).
+		If(r2.IsAncestorOf(r3)).Should().Equal(true).
+		If(r2.IsAncestorOf(r5)).Should().Equal(true).
+		If(r3.IsAncestorOf(r2)).Should().Equal(false).
+		If(r3.IsDescendantOf(r2)).Should().Equal(true).
+		If(r2.IsDescendantOf(r3)).Should().Equal(false).
+		If(r0.IsAncestorOf(r1)).Should().Equal(true).
+		If(iri.New("x").IsAncestorOf(r2)).Should().Equal(false)
+}
+
+func TestIsAncestorOfSymmetry(t *testing.T) {
+	test := []iri.ID{r0, r1, r2, r3, r4, r5, iri.New("x"), iri.New("a:x")}
+
+	for _, a := range test {
+		it.Ok(t).
+			If(a.IsAncestorOf(a)).Should().Equal(false).
+			If(a.IsDescendantOf(a)).Should().Equal(false)
+
+		for _, b := range test {
+			it.Ok(t).
+				If(a.IsAncestorOf(b)).Should().Equal(b.IsDescendantOf(a))
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	it.Ok(t).
+		If(r0.Compare(r0)).Should().Equal(0).
+		If(r0.Compare(r1)).Should().Equal(-1).
+		If(r1.Compare(r0)).Should().Equal(1).
+		If(r3.Compare(r3)).Should().Equal(0).
+		If(r3.Compare(iri.New("a:b:d"))).Should().Equal(-1).
+		If(iri.New("a:b:d").Compare(r3)).Should().Equal(1).
+		If(r2.Compare(r3)).Should().Equal(-1).
+		If(r3.Compare(r2)).Should().Equal(1).
+		If(iri.New("a:c").Compare(r3)).Should().Equal(1)
+}
+
+func TestCompareSort(t *testing.T) {
+	ids := []iri.ID{r5, r2, iri.New("b"), r0, r4, r1, r3}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Compare(ids[j]) < 0 })
+
+	it.Ok(t).
+		If(ids).Should().Equal([]iri.ID{r0, r1, r2, r3, r4, r5, iri.New("b")})
+
+	for _, a := range ids {
+		for _, b := range ids {
+			it.Ok(t).If(a.Compare(b) == 0).Should().Equal(a.Eq(b))
+		}
+	}
+}
+
+func TestCompareWith(t *testing.T) {
+	// tenant ascending, timestamp descending
+	cmp := func(pos int, a, b string) int {
+		if pos == 1 {
+			return strings.Compare(b, a)
+		}
+		return strings.Compare(a, b)
+	}
+
+	it.Ok(t).
+		If(iri.New("a:2").CompareWith(iri.New("a:1"), cmp)).Should().Equal(-1).
+		If(iri.New("a:1").CompareWith(iri.New("a:2"), cmp)).Should().Equal(1).
+		If(iri.New("a:1").CompareWith(iri.New("b:2"), cmp)).Should().Equal(-1).
+		If(iri.New("a:1").CompareWith(iri.New("a:1"), cmp)).Should().Equal(0).
+		If(iri.New("a:1").CompareWith(iri.New("a:1:x"), cmp)).Should().Equal(-1).
+		If(iri.New("a:1:x").CompareWith(iri.New("a:1"), cmp)).Should().Equal(1).
+		If(r0.CompareWith(r1, cmp)).Should().Equal(-1).
+		If(r0.CompareWith(r0, cmp)).Should().Equal(0)
+}
+
+func TestJSON(t *testing.T) {
+	type Struct struct {
+		iri.ID
+		Title string `json:"title"`
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.New(""), Title: "t"}:      "{\"id\":\"\",\"title\":\"t\"}",
+		{ID: iri.New("a"), Title: "t"}:     "{\"id\":\"a\",\"title\":\"t\"}",
+		{ID: iri.New("a:b"), Title: "t"}:   "{\"id\":\"a:b\",\"title\":\"t\"}",
+		{ID: iri.New("a:b:c"), Title: "t"}: "{\"id\":\"a:b:c\",\"title\":\"t\"}",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := json.Marshal(eg)
+		err2 := json.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(*eg).Should().Equal(in).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestJSONNull(t *testing.T) {
+	type Struct struct {
+		iri.ID
+		Title string `json:"title"`
+	}
+
+	in := Struct{ID: r3}
+	err := json.Unmarshal([]byte("{\"id\":null,\"title\":\"t\"}"), &in)
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(in).Should().Equal(Struct{ID: r0, Title: "t"})
+}
+
+func TestJSONInvalid(t *testing.T) {
+	type Struct struct {
+		iri.ID
+	}
+
+	test := map[string]string{
+		"{\"id\":1}":    "iri: expected JSON string, got number",
+		"{\"id\":{}}":   "iri: expected JSON string, got object",
+		"{\"id\":[]}":   "iri: expected JSON string, got array",
+		"{\"id\":true}": "iri: expected JSON string, got boolean",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+		err := json.Unmarshal([]byte(eg), &in)
+
+		it.Ok(t).
+			If(err).ShouldNot().Equal(nil).
+			If(strings.HasPrefix(err.Error(), expect)).Should().Equal(true)
+	}
+}
+
+func TestJSONEscape(t *testing.T) {
+	type Struct struct {
+		iri.ID
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.Join("a", "12:00", "b")}: "{\"id\":\"a:12%3A00:b\"}",
+		{ID: iri.Join("a", "100%")}:       "{\"id\":\"a:100%\"}",
+		{ID: iri.Join("a", "%25")}:        "{\"id\":\"a:%2525\"}",
+		{ID: iri.Join("a", "%3A")}:        "{\"id\":\"a:%253A\"}",
+		{ID: iri.Join(":", ":")}:          "{\"id\":\"%3A:%3A\"}",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := json.Marshal(eg)
+		err2 := json.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
 			If(*eg).Should().Equal(in).
 			If(string(bytes)).Should().Equal(expect)
 	}
 }
 
+func TestEscape(t *testing.T) {
+	id := iri.Join("a", "12:00", "b")
+
+	it.Ok(t).
+		If(id.IRI.String()).Should().Equal("a:12%3A00:b").
+		If(id.Prefix()).Should().Equal("a:12%3A00").
+		If(id.Suffix(2)).Should().Equal("12%3A00:b").
+		If(iri.New("%s", "a:12%3A00:b")).Should().Equal(id).
+		If(iri.New("%s", "a:12%3a00:b")).Should().Equal(id).
+		If(iri.New("%s", "a:100%:b").Segments()).Should().Equal([]string{"a", "100%", "b"}).
+		If(iri.Join("a", "100%").String()).Should().Equal("a:100%").
+		If(iri.Join("a", "100%", "%2").String()).Should().Equal("a:100%:%2").
+		If(iri.Join("a", "%25", "%3a").String()).Should().Equal("a:%2525:%253a").
+		If(iri.Join("%:").String()).Should().Equal("%%3A").
+		If(iri.New("%s", "%%3A")).Should().Equal(iri.Join("%:")).
+		If(iri.New("%s", "USER#1:ORDER#2").String()).Should().Equal("USER#1:ORDER#2").
+		If(iri.Join("a#b", "%23").String()).Should().Equal("a#b:%2523").
+		If(iri.New("%s", "a#b:%2523")).Should().Equal(iri.Join("a#b", "%23"))
+}
+
+func TestText(t *testing.T) {
+	test := map[*iri.ID]string{
+		&r0: "",
+		&r1: "a",
+		&r2: "a:b",
+		&r3: "a:b:c",
+	}
+
+	for eg, expect := range test {
+		var in iri.IRI
+
+		bytes, err1 := eg.IRI.MarshalText()
+		err2 := in.UnmarshalText(bytes)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(in).Should().Equal(eg.IRI).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestYAML(t *testing.T) {
+	type Struct struct {
+		iri.ID `yaml:",inline"`
+		Title  string `yaml:"title"`
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.New(""), Title: "t"}:            "id: \"\"\ntitle: t\n",
+		{ID: iri.New("a"), Title: "t"}:           "id: a\ntitle: t\n",
+		{ID: iri.New("a:b:c"), Title: "t"}:       "id: a:b:c\ntitle: t\n",
+		{ID: iri.Join("a", "12:00"), Title: "t"}: "id: a:12%3A00\ntitle: t\n",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := yaml.Marshal(eg)
+		err2 := yaml.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(*eg).Should().Equal(in).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestYAMLField(t *testing.T) {
+	type Struct struct {
+		Key iri.ID  `yaml:"key"`
+		Ref iri.IRI `yaml:"ref"`
+	}
+
+	eg := Struct{Key: iri.New("a:b"), Ref: iri.NewIRI("a:b")}
+	in := Struct{}
+
+	bytes, err1 := yaml.Marshal(eg)
+	err2 := yaml.Unmarshal(bytes, &in)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(in).Should().Equal(eg).
+		If(string(bytes)).Should().Equal("key:\n    id: a:b\nref: a:b\n")
+}
+
+func TestXML(t *testing.T) {
+	type Struct struct {
+		XMLName xml.Name `xml:"struct"`
+		iri.ID
+		Title string `xml:"title"`
+	}
+
+	test := map[*Struct]string{
+		{ID: iri.New(""), Title: "t"}:      "<struct><id></id><title>t</title></struct>",
+		{ID: iri.New("a"), Title: "t"}:     "<struct><id>a</id><title>t</title></struct>",
+		{ID: iri.New("a:b:c"), Title: "t"}: "<struct><id>a:b:c</id><title>t</title></struct>",
+	}
+
+	for eg, expect := range test {
+		in := Struct{}
+
+		bytes, err1 := xml.Marshal(eg)
+		err2 := xml.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(in.ID).Should().Equal(eg.ID).
+			If(in.Title).Should().Equal(eg.Title).
+			If(string(bytes)).Should().Equal(expect)
+	}
+}
+
+func TestGob(t *testing.T) {
+	type Struct struct {
+		iri.ID
+		Title string
+	}
+
+	eg := []Struct{
+		{ID: r0, Title: "t"},
+		{ID: r3, Title: "t"},
+		{ID: r1, Title: "t"},
+		{ID: r5, Title: "t"},
+	}
+	in := []Struct{}
+
+	var buf bytes.Buffer
+	err1 := gob.NewEncoder(&buf).Encode(eg)
+	err2 := gob.NewDecoder(&buf).Decode(&in)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(in).Should().Equal(eg)
+}
+
+func TestSQL(t *testing.T) {
+	test := map[*iri.ID]interface{}{
+		&r0: nil,
+		&r1: "a",
+		&r2: "a:b",
+		&r3: "a:b:c",
+	}
+
+	for eg, expect := range test {
+		var in iri.ID
+
+		val, err1 := eg.Value()
+		err2 := in.Scan(val)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(val).Should().Equal(driver.Value(expect)).
+			If(in).Should().Equal(*eg)
+	}
+}
+
+func TestSQLScan(t *testing.T) {
+	var a, b, c iri.ID
+
+	it.Ok(t).
+		If(a.Scan("a:b")).Should().Equal(nil).
+		If(a).Should().Equal(r2).
+		If(b.Scan([]byte("a:b:c"))).Should().Equal(nil).
+		If(b).Should().Equal(r3).
+		If(c.Scan(nil)).Should().Equal(nil).
+		If(c).Should().Equal(r0).
+		If(c.Scan(int64(1))).ShouldNot().Equal(nil)
+}
+
 func TestDynamo(t *testing.T) {
 	type Struct struct {
 		iri.ID
@@ -203,6 +1550,68 @@ func TestDynamo(t *testing.T) {
 	}
 }
 
+func TestMaxSegments(t *testing.T) {
+	type Struct struct {
+		iri.ID
+	}
+
+	deep := func(n int) iri.ID {
+		seq := make([]string, n)
+		for i := range seq {
+			seq[i] = "a"
+		}
+		return iri.Join(seq...)
+	}
+
+	for _, n := range []int{iri.MaxSegments, iri.MaxSegments + 1} {
+		var (
+			fromJSON Struct
+			fromDDB  Struct
+			fromPath iri.PathID
+		)
+
+		b, _ := json.Marshal(Struct{ID: deep(n)})
+		err1 := json.Unmarshal(b, &fromJSON)
+
+		gen, _ := dynamodbattribute.MarshalMap(Struct{ID: deep(n)})
+		err2 := dynamodbattribute.UnmarshalMap(gen, &fromDDB)
+
+		p, _ := json.Marshal(iri.PathID{ID: deep(n)})
+		err3 := json.Unmarshal(p, &fromPath)
+
+		if n <= iri.MaxSegments {
+			it.Ok(t).
+				If(err1).Should().Equal(nil).
+				If(err2).Should().Equal(nil).
+				If(err3).Should().Equal(nil).
+				If(fromJSON.Len()).Should().Equal(n).
+				If(fromDDB.Len()).Should().Equal(n).
+				If(fromPath.Len()).Should().Equal(n)
+		} else {
+			it.Ok(t).
+				If(err1).ShouldNot().Equal(nil).
+				If(err2).ShouldNot().Equal(nil).
+				If(err3).ShouldNot().Equal(nil)
+		}
+	}
+}
+
+func TestMaxSegmentsTuned(t *testing.T) {
+	defer func(n int) { iri.MaxSegments = n }(iri.MaxSegments)
+
+	var id iri.IRI
+
+	iri.MaxSegments = 2
+	it.Ok(t).
+		If(id.UnmarshalJSON([]byte("\"a:b\""))).Should().Equal(nil).
+		If(id.UnmarshalJSON([]byte("\"a:b:c\""))).ShouldNot().Equal(nil)
+
+	iri.MaxSegments = 0
+	it.Ok(t).
+		If(id.UnmarshalJSON([]byte("\"a:b:c\""))).Should().Equal(nil).
+		If(id).Should().Equal(r3.IRI)
+}
+
 func TestTypeSafe(t *testing.T) {
 	type A struct{ iri.ID }
 	type B struct{ iri.ID }
@@ -217,3 +1626,46 @@ func TestTypeSafe(t *testing.T) {
 		If(b.ID).Should().Equal(r2).
 		If(c.ID).Should().Equal(r3)
 }
+
+func BenchmarkString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = r5.String()
+	}
+}
+
+func BenchmarkStringSingle(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = r1.String()
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	var buf bytes.Buffer
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		r5.WriteTo(&buf)
+	}
+}
+
+func BenchmarkWriteString(b *testing.B) {
+	var buf bytes.Buffer
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		io.WriteString(&buf, r5.String())
+	}
+}
+
+func BenchmarkStringEscape(b *testing.B) {
+	id := iri.Join("a", "12:00", "b")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.String()
+	}
+}
@@ -0,0 +1,50 @@
+/*
+
+Package msgpack encodes IRI as MessagePack string in compact form
+"prefix:suffix", instead of the map of segments. The codec is registered
+at github.com/vmihailenco/msgpack once per process.
+
+  import codec "github.com/fogfish/iri/msgpack"
+
+  codec.Register()
+  msgpack.Marshal(iri.NewIRI("a:b")) ⟼ "a:b"
+*/
+package msgpack
+
+import (
+	"reflect"
+
+	"github.com/fogfish/iri"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+/*
+
+Register installs IRI codec for msgpack.Marshal and msgpack.Unmarshal,
+the empty IRI is encoded as empty string. The ID is encoded as IRI under
+the key "id".
+*/
+func Register() {
+	msgpack.Register(iri.IRI{}, encode, decode)
+}
+
+// encode `IRI ⟼ "prefix:suffix"`
+func encode(enc *msgpack.Encoder, v reflect.Value) error {
+	return enc.EncodeString(v.Interface().(iri.IRI).String())
+}
+
+// decode `"prefix:suffix" ⟼ IRI`
+func decode(dec *msgpack.Decoder, v reflect.Value) error {
+	path, err := dec.DecodeString()
+	if err != nil {
+		return err
+	}
+
+	var id iri.IRI
+	if err := id.UnmarshalText([]byte(path)); err != nil {
+		return err
+	}
+
+	v.Set(reflect.ValueOf(id))
+	return nil
+}
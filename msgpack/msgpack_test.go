@@ -0,0 +1,66 @@
+package msgpack_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	codec "github.com/fogfish/iri/msgpack"
+	"github.com/fogfish/it"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func init() {
+	codec.Register()
+}
+
+func TestMsgpack(t *testing.T) {
+	type Struct struct {
+		iri.ID
+		Title string `msgpack:"title"`
+	}
+
+	test := []Struct{
+		{ID: iri.New(""), Title: "t"},
+		{ID: iri.New("a"), Title: "t"},
+		{ID: iri.New("a:b:c"), Title: "t"},
+		{ID: iri.Join("a", "12:00"), Title: "t"},
+	}
+
+	for _, eg := range test {
+		in := Struct{}
+
+		bytes, err1 := msgpack.Marshal(eg)
+		err2 := msgpack.Unmarshal(bytes, &in)
+
+		it.Ok(t).
+			If(err1).Should().Equal(nil).
+			If(err2).Should().Equal(nil).
+			If(in).Should().Equal(eg)
+	}
+}
+
+func TestMsgpackCompact(t *testing.T) {
+	type Struct struct {
+		iri.ID
+		Title string `msgpack:"title"`
+	}
+
+	bytes, err1 := msgpack.Marshal(Struct{ID: iri.New("a:b"), Title: "t"})
+
+	var in map[string]interface{}
+	err2 := msgpack.Unmarshal(bytes, &in)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(in).Should().Equal(map[string]interface{}{"id": "a:b", "title": "t"})
+}
+
+func TestMsgpackEmpty(t *testing.T) {
+	bytes, err := msgpack.Marshal(iri.New("").IRI)
+	expect, _ := msgpack.Marshal("")
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(bytes).Should().Equal(expect)
+}
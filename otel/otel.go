@@ -0,0 +1,35 @@
+/*
+
+Package otel annotates OpenTelemetry spans and metrics with IRI, the
+identity is exported as a set of attributes, see Attributes.
+*/
+package otel
+
+import (
+	"github.com/fogfish/iri"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+/*
+
+Attributes returns IRI components as attributes set:
+
+  prefix.depth ⟼ number of segments
+  prefix.root  ⟼ first segment
+  prefix.leaf  ⟼ last segment
+  prefix.full  ⟼ compact IRI "a:b:c"
+*/
+func Attributes(id iri.ID, prefix string) []attribute.KeyValue {
+	root, leaf := "", ""
+	if n := id.Len(); n > 0 {
+		root, _ = id.Segment(0)
+		leaf, _ = id.Segment(n - 1)
+	}
+
+	return []attribute.KeyValue{
+		attribute.Int(prefix+".depth", id.Len()),
+		attribute.String(prefix+".root", root),
+		attribute.String(prefix+".leaf", leaf),
+		attribute.String(prefix+".full", id.String()),
+	}
+}
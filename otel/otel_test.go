@@ -0,0 +1,34 @@
+package otel_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/iri/otel"
+	"github.com/fogfish/it"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttributes(t *testing.T) {
+	expect := []attribute.KeyValue{
+		attribute.Int("id.depth", 3),
+		attribute.String("id.root", "a"),
+		attribute.String("id.leaf", "c"),
+		attribute.String("id.full", "a:b:c"),
+	}
+
+	it.Ok(t).
+		If(otel.Attributes(iri.New("a:b:c"), "id")).Should().Equal(expect)
+}
+
+func TestAttributesEmpty(t *testing.T) {
+	expect := []attribute.KeyValue{
+		attribute.Int("id.depth", 0),
+		attribute.String("id.root", ""),
+		attribute.String("id.leaf", ""),
+		attribute.String("id.full", ""),
+	}
+
+	it.Ok(t).
+		If(otel.Attributes(iri.New(""), "id")).Should().Equal(expect)
+}
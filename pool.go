@@ -0,0 +1,33 @@
+package iri
+
+import "sync"
+
+/*
+
+Pool interns IRIs, equal inputs produce IDs that share the backing array of
+segments. It reduces heap usage when same identifiers appear many times.
+The zero value is an empty pool ready to use. The pool is safe for
+concurrent use.
+
+IDs produced by pool share memory, segments must not be modified in place.
+
+  var pool iri.Pool
+  pool.New("a:b:c")
+*/
+type Pool struct {
+	ids sync.Map
+}
+
+/*
+
+New parses a compact IRI string, see iri.New, and returns the canonical
+instance of IRI from the pool.
+*/
+func (p *Pool) New(s string) ID {
+	if v, has := p.ids.Load(s); has {
+		return ID{IRI: v.(IRI)}
+	}
+
+	v, _ := p.ids.LoadOrStore(s, parse(s))
+	return ID{IRI: v.(IRI)}
+}
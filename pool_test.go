@@ -0,0 +1,53 @@
+package iri_test
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestPool(t *testing.T) {
+	var pool iri.Pool
+
+	a := pool.New("a:b:c")
+	b := pool.New("a:b:c")
+	c := pool.New("a:b:d")
+
+	it.Ok(t).
+		If(a).Should().Equal(r3).
+		If(b).Should().Equal(r3).
+		If(&a.IRI.Seq[0] == &b.IRI.Seq[0]).Should().Equal(true).
+		If(&a.IRI.Seq[0] == &c.IRI.Seq[0]).Should().Equal(false).
+		If(a.Heir("d")).Should().Equal(r4).
+		If(pool.New("a:b:c")).Should().Equal(r3)
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	var (
+		pool iri.Pool
+		wg   sync.WaitGroup
+	)
+
+	ids := make([][]iri.ID, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				ids[i] = append(ids[i], pool.New("a:b:"+strconv.Itoa(k)))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range ids {
+		for k, id := range ids[i] {
+			it.Ok(t).
+				If(id).Should().Equal(iri.New("a:b:%d", k)).
+				If(&id.IRI.Seq[0] == &ids[0][k].IRI.Seq[0]).Should().Equal(true)
+		}
+	}
+}
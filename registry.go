@@ -0,0 +1,136 @@
+package iri
+
+import (
+	"sort"
+	"sync"
+)
+
+/*
+
+Registry maps IRIs to values, it is backed by segment trie so that values
+are queried by prefix. Lookups do not build string keys. The zero value is
+an empty registry ready to use. The registry is safe for concurrent use by
+readers and a single writer.
+
+  var reg iri.Registry[string]
+  reg.Store(iri.New("tenant:acme:order:1"), "order")
+  reg.LoadPrefix(iri.New("tenant:acme")) ⟼ ["order"]
+*/
+type Registry[T any] struct {
+	mu   sync.RWMutex
+	root registryNode[T]
+}
+
+type registryNode[T any] struct {
+	value    T
+	stored   bool
+	children map[string]*registryNode[T]
+}
+
+/*
+
+Store sets the value of IRI, the previous value is replaced
+*/
+func (reg *Registry[T]) Store(id ID, value T) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	node := &reg.root
+	for _, seg := range id.IRI.seq() {
+		if node.children == nil {
+			node.children = map[string]*registryNode[T]{}
+		}
+
+		next, has := node.children[seg]
+		if !has {
+			next = &registryNode[T]{}
+			node.children[seg] = next
+		}
+		node = next
+	}
+
+	node.value = value
+	node.stored = true
+}
+
+/*
+
+Load returns the value of IRI and true if it is stored
+*/
+func (reg *Registry[T]) Load(id ID) (T, bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	if node := reg.lookup(id); node != nil && node.stored {
+		return node.value, true
+	}
+
+	var none T
+	return none, false
+}
+
+/*
+
+LoadPrefix returns values of IRI and all its descendants, values are ordered
+from the IRI to leafs, siblings are ordered by segment.
+*/
+func (reg *Registry[T]) LoadPrefix(prefix ID) []T {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	seq := []T{}
+	if node := reg.lookup(prefix); node != nil {
+		node.walk(prefix.IRI.seq(), func(_ []string, value T) bool {
+			seq = append(seq, value)
+			return true
+		})
+	}
+
+	return seq
+}
+
+/*
+
+Range calls fn for each stored IRI and its value in order of LoadPrefix,
+it stops if fn returns false. The fn must not store values into registry.
+*/
+func (reg *Registry[T]) Range(fn func(ID, T) bool) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	reg.root.walk(nil, func(path []string, value T) bool {
+		return fn(ID{IRI: IRI{}.Heirs(path...)}, value)
+	})
+}
+
+func (reg *Registry[T]) lookup(id ID) *registryNode[T] {
+	node := &reg.root
+	for _, seg := range id.IRI.seq() {
+		if node = node.children[seg]; node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// walk visits stored values depth-first, it returns false if visit is stopped
+func (node *registryNode[T]) walk(path []string, fn func([]string, T) bool) bool {
+	if node.stored && !fn(path, node.value) {
+		return false
+	}
+
+	keys := make([]string, 0, len(node.children))
+	for key := range node.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !node.children[key].walk(append(path[:len(path):len(path)], key), fn) {
+			return false
+		}
+	}
+
+	return true
+}
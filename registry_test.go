@@ -0,0 +1,108 @@
+package iri_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestRegistry(t *testing.T) {
+	var reg iri.Registry[string]
+	reg.Store(r2, "r2")
+	reg.Store(r4, "r4")
+	reg.Store(iri.Join("a", "b:c"), "b:c")
+	reg.Store(r2, "r2'")
+
+	v2, ok2 := reg.Load(r2)
+	v4, ok4 := reg.Load(r4)
+	v3, ok3 := reg.Load(r3)
+	vx, okx := reg.Load(iri.New("x"))
+	vc, okc := reg.Load(iri.Join("a", "b:c"))
+
+	it.Ok(t).
+		If(ok2).Should().Equal(true).
+		If(v2).Should().Equal("r2'").
+		If(ok4).Should().Equal(true).
+		If(v4).Should().Equal("r4").
+		If(ok3).Should().Equal(false).
+		If(v3).Should().Equal("").
+		If(okx).Should().Equal(false).
+		If(vx).Should().Equal("").
+		If(okc).Should().Equal(true).
+		If(vc).Should().Equal("b:c")
+}
+
+func TestRegistryLoadPrefix(t *testing.T) {
+	var reg iri.Registry[int]
+	reg.Store(r0, 0)
+	reg.Store(r2, 2)
+	reg.Store(r4, 4)
+	reg.Store(iri.New("a:b:x"), 3)
+	reg.Store(iri.New("a:c"), 5)
+
+	it.Ok(t).
+		If(reg.LoadPrefix(r2)).Should().Equal([]int{2, 4, 3}).
+		If(reg.LoadPrefix(r3)).Should().Equal([]int{4}).
+		If(reg.LoadPrefix(r1)).Should().Equal([]int{2, 4, 3, 5}).
+		If(reg.LoadPrefix(r0)).Should().Equal([]int{0, 2, 4, 3, 5}).
+		If(reg.LoadPrefix(iri.New("x"))).Should().Equal([]int{})
+}
+
+func TestRegistryRange(t *testing.T) {
+	var reg iri.Registry[int]
+	reg.Store(r3, 3)
+	reg.Store(r1, 1)
+	reg.Store(iri.New("a:c"), 2)
+
+	ids, vals := []iri.ID{}, []int{}
+	reg.Range(func(id iri.ID, v int) bool {
+		ids = append(ids, id)
+		vals = append(vals, v)
+		return true
+	})
+
+	it.Ok(t).
+		If(ids).Should().Equal([]iri.ID{r1, r3, iri.New("a:c")}).
+		If(vals).Should().Equal([]int{1, 3, 2})
+
+	n := 0
+	reg.Range(func(iri.ID, int) bool {
+		n++
+		return false
+	})
+
+	it.Ok(t).If(n).Should().Equal(1)
+}
+
+func TestRegistryConcurrent(t *testing.T) {
+	var (
+		reg iri.Registry[int]
+		wg  sync.WaitGroup
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			reg.Store(iri.New("a:b:%d", i), i)
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if v, ok := reg.Load(iri.New("a:b:%d", i)); ok && v != i {
+					t.Errorf("unexpected value %d for %d", v, i)
+				}
+				reg.LoadPrefix(r2)
+			}
+		}()
+	}
+	wg.Wait()
+
+	it.Ok(t).If(len(reg.LoadPrefix(r2))).Should().Equal(100)
+}
@@ -0,0 +1,112 @@
+package iri
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+/*
+
+RingPosition returns a stable position of IRI on the consistent hashing ring.
+The position is 32-bit FNV-1a hash of the compact IRI string (e.g. "a:b:c"),
+all nodes agree on positions as long as they use same compact form.
+*/
+func (iri ID) RingPosition() uint32 {
+	return iri.IRI.RingPosition()
+}
+
+/*
+
+RingPosition returns a stable position of IRI on the consistent hashing ring
+*/
+func (iri IRI) RingPosition() uint32 {
+	return ringHash(iri.String())
+}
+
+func ringHash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+/*
+
+Ring is consistent hashing ring, it distributes IRIs across nodes.
+Each node is placed on the ring multiple times (virtual nodes) at positions
+computed by 32-bit FNV-1a hash of "node#i". An IRI belongs to the first
+node clockwise from its RingPosition.
+
+The ring is not safe for concurrent mutation.
+*/
+type Ring struct {
+	vnodes int
+	points []uint32
+	owners map[uint32]string
+}
+
+/*
+
+NewRing creates an empty ring, each node has vnodes positions on the ring.
+*/
+func NewRing(vnodes int) *Ring {
+	if vnodes < 1 {
+		vnodes = 1
+	}
+
+	return &Ring{
+		vnodes: vnodes,
+		points: []uint32{},
+		owners: map[uint32]string{},
+	}
+}
+
+/*
+
+AddNode places the node on the ring
+*/
+func (ring *Ring) AddNode(node string) {
+	for i := 0; i < ring.vnodes; i++ {
+		p := ringHash(node + "#" + strconv.Itoa(i))
+		if _, exists := ring.owners[p]; !exists {
+			ring.points = append(ring.points, p)
+		}
+		ring.owners[p] = node
+	}
+
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i] < ring.points[j] })
+}
+
+/*
+
+RemoveNode removes the node from the ring
+*/
+func (ring *Ring) RemoveNode(node string) {
+	points := ring.points[:0]
+	for _, p := range ring.points {
+		if ring.owners[p] == node {
+			delete(ring.owners, p)
+			continue
+		}
+		points = append(points, p)
+	}
+	ring.points = points
+}
+
+/*
+
+Locate returns node responsible for the IRI, false if the ring is empty.
+*/
+func (ring *Ring) Locate(id ID) (string, bool) {
+	if len(ring.points) == 0 {
+		return "", false
+	}
+
+	p := id.RingPosition()
+	i := sort.Search(len(ring.points), func(i int) bool { return ring.points[i] >= p })
+	if i == len(ring.points) {
+		i = 0
+	}
+
+	return ring.owners[ring.points[i]], true
+}
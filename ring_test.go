@@ -0,0 +1,58 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestRingPosition(t *testing.T) {
+	it.Ok(t).
+		If(r3.RingPosition()).Should().Equal(iri.New("a:b:c").RingPosition()).
+		If(r3.RingPosition()).Should().Equal(uint32(0x6d4c7897)).
+		If(r3.RingPosition()).ShouldNot().Equal(r2.RingPosition())
+}
+
+func TestRingLocate(t *testing.T) {
+	ring := iri.NewRing(16)
+
+	_, found := ring.Locate(r3)
+	it.Ok(t).If(found).Should().Equal(false)
+
+	ring.AddNode("a")
+	ring.AddNode("b")
+	ring.AddNode("c")
+
+	for i := 0; i < 100; i++ {
+		id := iri.New("a:b:%d", i)
+		n1, f1 := ring.Locate(id)
+		n2, f2 := ring.Locate(id)
+		it.Ok(t).
+			If(f1).Should().Equal(true).
+			If(f2).Should().Equal(true).
+			If(n1).Should().Equal(n2)
+	}
+}
+
+func TestRingRemoveNode(t *testing.T) {
+	ring := iri.NewRing(16)
+	ring.AddNode("a")
+	ring.AddNode("b")
+	ring.AddNode("c")
+
+	before := map[int]string{}
+	for i := 0; i < 1000; i++ {
+		before[i], _ = ring.Locate(iri.New("a:b:%d", i))
+	}
+
+	ring.RemoveNode("c")
+
+	for i := 0; i < 1000; i++ {
+		node, _ := ring.Locate(iri.New("a:b:%d", i))
+		it.Ok(t).If(node).ShouldNot().Equal("c")
+		if before[i] != "c" {
+			it.Ok(t).If(node).Should().Equal(before[i])
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package iri
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+/*
+
+ParseStream reads newline-delimited compact IRIs from reader and calls fn
+for each one. Empty lines are skipped. It stops on the first error either
+returned by reader or by fn. The read buffer is reused across lines.
+*/
+func ParseStream(r io.Reader, fn func(ID) error) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var long []byte
+
+	for {
+		line, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			long = append(long, line...)
+			continue
+		}
+
+		if len(long) > 0 {
+			line = append(long, line...)
+			long = long[:0]
+		}
+
+		if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+			if fault := fn(ID{IRI: parse(string(line))}); fault != nil {
+				return fault
+			}
+		}
+
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+/*
+
+WriteCSV writes IRIs to writer as single column CSV, one compact IRI per
+record. The compact IRI is escaped with String, CSV quoting is applied to
+commas, quotes and line breaks. The empty IRI is written as quoted empty
+field "" so that it is not lost as an empty line.
+*/
+func WriteCSV(w io.Writer, ids []ID) error {
+	writer := csv.NewWriter(w)
+	record := make([]string, 1)
+
+	for _, id := range ids {
+		if id.IRI.IsEmpty() {
+			writer.Flush()
+			if _, err := io.WriteString(w, "\"\"\n"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record[0] = id.IRI.String()
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+/*
+
+ReadCSV reads IRIs from single column CSV produced by WriteCSV
+*/
+func ReadCSV(r io.Reader) ([]ID, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 1
+	reader.ReuseRecord = true
+
+	ids := []ID{}
+	for {
+		record, err := reader.Read()
+		switch {
+		case err == io.EOF:
+			return ids, nil
+		case err != nil:
+			return nil, err
+		}
+
+		ids = append(ids, ID{IRI: parse(record[0])})
+	}
+}
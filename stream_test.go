@@ -0,0 +1,119 @@
+package iri_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestParseStream(t *testing.T) {
+	seq := []iri.ID{}
+	err := iri.ParseStream(
+		strings.NewReader("a\na:b\r\n\na:b:c"),
+		func(id iri.ID) error {
+			seq = append(seq, id)
+			return nil
+		},
+	)
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(seq).Should().Equal([]iri.ID{r1, r2, r3})
+}
+
+func TestParseStreamLongLine(t *testing.T) {
+	long := strings.Repeat("a", 100000)
+
+	seq := []iri.ID{}
+	err := iri.ParseStream(
+		strings.NewReader("a:"+long+"\na:b\n"),
+		func(id iri.ID) error {
+			seq = append(seq, id)
+			return nil
+		},
+	)
+
+	it.Ok(t).
+		If(err).Should().Equal(nil).
+		If(seq).Should().Equal([]iri.ID{iri.Join("a", long), r2})
+}
+
+func TestParseStreamStop(t *testing.T) {
+	stop := errors.New("stop")
+
+	seq := []iri.ID{}
+	err := iri.ParseStream(
+		strings.NewReader("a\na:b\na:b:c\n"),
+		func(id iri.ID) error {
+			seq = append(seq, id)
+			if id.Len() == 2 {
+				return stop
+			}
+			return nil
+		},
+	)
+
+	it.Ok(t).
+		If(err).Should().Equal(stop).
+		If(seq).Should().Equal([]iri.ID{r1, r2})
+}
+
+func BenchmarkParseStream(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 100000; i++ {
+		sb.WriteString("tenant:" + strconv.Itoa(i%100) + ":order:" + strconv.Itoa(i) + "\n")
+	}
+	corpus := sb.String()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		iri.ParseStream(strings.NewReader(corpus), func(iri.ID) error { return nil })
+	}
+}
+
+func TestCSV(t *testing.T) {
+	test := []iri.ID{
+		r3,
+		r0,
+		iri.Join("a,b", "c"),
+		iri.Join("say \"hi\"", "x"),
+		iri.Join("a", "12:00", "100%"),
+		iri.Join("multi\nline", "x"),
+		r0,
+		iri.Join(" a ", "b"),
+	}
+
+	var buf bytes.Buffer
+	err1 := iri.WriteCSV(&buf, test)
+	seq, err2 := iri.ReadCSV(&buf)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(seq).Should().Equal(test)
+}
+
+func TestCSVEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	err1 := iri.WriteCSV(&buf, nil)
+	seq, err2 := iri.ReadCSV(&buf)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(seq).Should().Equal([]iri.ID{})
+}
+
+func TestReadCSVFailure(t *testing.T) {
+	_, err := iri.ReadCSV(strings.NewReader("a:b,c:d\n"))
+	it.Ok(t).If(err).ShouldNot().Equal(nil)
+
+	_, err = iri.ReadCSV(strings.NewReader("\"a:b\n"))
+	it.Ok(t).If(err).ShouldNot().Equal(nil)
+}
@@ -0,0 +1,54 @@
+package iri
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+
+Template builds IRI from template by substituting named placeholders {name}
+with values from vars. The value is confined to segment, the separator ":"
+within value does not split it. Literal braces are escaped as "{{" and "}}".
+It fails if placeholder is not closed or value is missing.
+
+  iri.Template("tenant:{tenant}:order:{id}", map[string]string{
+    "tenant": "acme",
+    "id":     "42",
+  }) ⟼ tenant:acme:order:42
+*/
+func Template(tmpl string, vars map[string]string) (ID, error) {
+	var sb strings.Builder
+	sb.Grow(len(tmpl))
+
+	for i := 0; i < len(tmpl); i++ {
+		switch c := tmpl[i]; {
+		case c == '{' && strings.HasPrefix(tmpl[i:], "{{"):
+			sb.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(tmpl[i:], "}}"):
+			sb.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end == -1 {
+				return ID{}, fmt.Errorf("iri: unclosed placeholder at %d in %q", i, tmpl)
+			}
+
+			key := tmpl[i+1 : i+end]
+			val, has := vars[key]
+			if !has {
+				return ID{}, fmt.Errorf("iri: missing value for placeholder {%s} in %q", key, tmpl)
+			}
+
+			sb.WriteString(join([]string{val}))
+			i += end
+		case c == '}':
+			return ID{}, fmt.Errorf("iri: unexpected } at %d in %q", i, tmpl)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return ID{IRI: parse(sb.String())}, nil
+}
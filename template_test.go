@@ -0,0 +1,44 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestTemplate(t *testing.T) {
+	vars := map[string]string{"tenant": "acme", "id": "42", "at": "12:00"}
+
+	test := map[string]iri.ID{
+		"tenant:{tenant}:order:{id}": iri.New("tenant:acme:order:42"),
+		"{tenant}":                   iri.New("acme"),
+		"order:{id}-{tenant}":        iri.New("order:42-acme"),
+		"order:{at}":                 iri.Join("order", "12:00"),
+		"a:{{id}}":                   iri.Join("a", "{id}"),
+		"a:{{{id}}}":                 iri.Join("a", "{42}"),
+		"a:b":                        r2,
+		"":                           r0,
+	}
+
+	for tmpl, expect := range test {
+		id, err := iri.Template(tmpl, vars)
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(id).Should().Equal(expect)
+	}
+}
+
+func TestTemplateFailure(t *testing.T) {
+	vars := map[string]string{"id": "42"}
+
+	for _, tmpl := range []string{
+		"order:{tenant}",
+		"order:{id",
+		"order:{",
+		"order:id}",
+	} {
+		_, err := iri.Template(tmpl, vars)
+		it.Ok(t).If(err).ShouldNot().Equal(nil)
+	}
+}
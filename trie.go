@@ -0,0 +1,86 @@
+package iri
+
+/*
+
+PrefixTrie is a set of IRI prefixes backed by segment trie, it answers
+whether IRI is covered by any prefix in O(depth) of IRI. The prefix covers
+itself and all its descendants, the empty IRI covers any IRI. The zero value
+is an empty set ready to use.
+
+The trie is not safe for concurrent mutation.
+*/
+type PrefixTrie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	terminal bool
+	children map[string]*trieNode
+}
+
+/*
+
+Insert adds the prefix to the set
+*/
+func (t *PrefixTrie) Insert(prefix ID) {
+	node := &t.root
+	for _, seg := range prefix.IRI.seq() {
+		if node.children == nil {
+			node.children = map[string]*trieNode{}
+		}
+
+		next, has := node.children[seg]
+		if !has {
+			next = &trieNode{}
+			node.children[seg] = next
+		}
+		node = next
+	}
+
+	node.terminal = true
+}
+
+/*
+
+Covers returns true if any prefix in the set is prefix of IRI (or equal to it)
+*/
+func (t *PrefixTrie) Covers(id ID) bool {
+	return t.depth(id) != -1
+}
+
+/*
+
+Longest returns the longest prefix in the set that covers IRI
+
+  t.Insert(iri.New("a"))
+  t.Insert(iri.New("a:b"))
+  t.Longest(iri.New("a:b:c")) ⟼ a:b, true
+*/
+func (t *PrefixTrie) Longest(id ID) (ID, bool) {
+	depth := t.depth(id)
+	if depth == -1 {
+		return ID{}, false
+	}
+
+	return id.Truncate(depth), true
+}
+
+// depth of the longest prefix covering IRI, -1 if none
+func (t *PrefixTrie) depth(id ID) int {
+	node, depth := &t.root, -1
+	if node.terminal {
+		depth = 0
+	}
+
+	for i, seg := range id.IRI.seq() {
+		if node = node.children[seg]; node == nil {
+			break
+		}
+
+		if node.terminal {
+			depth = i + 1
+		}
+	}
+
+	return depth
+}
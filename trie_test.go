@@ -0,0 +1,91 @@
+package iri_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestPrefixTrie(t *testing.T) {
+	var trie iri.PrefixTrie
+	trie.Insert(iri.New("a:b"))
+	trie.Insert(iri.New("a:b:c:d"))
+	trie.Insert(iri.New("x"))
+
+	test := map[*iri.ID]iri.ID{
+		&r2: r2,
+		&r3: r2,
+		&r4: r4,
+		&r5: r4,
+	}
+
+	for q, expect := range test {
+		prefix, ok := trie.Longest(*q)
+		it.Ok(t).
+			If(ok).Should().Equal(true).
+			If(prefix).Should().Equal(expect).
+			If(trie.Covers(*q)).Should().Equal(true)
+	}
+
+	it.Ok(t).
+		If(trie.Covers(iri.New("x:y:z"))).Should().Equal(true).
+		If(trie.Covers(r0)).Should().Equal(false).
+		If(trie.Covers(r1)).Should().Equal(false).
+		If(trie.Covers(iri.New("a:x"))).Should().Equal(false).
+		If(trie.Covers(iri.New("b:a"))).Should().Equal(false)
+
+	_, ok := trie.Longest(iri.New("a:c"))
+	it.Ok(t).If(ok).Should().Equal(false)
+}
+
+func TestPrefixTrieRoot(t *testing.T) {
+	var trie iri.PrefixTrie
+
+	it.Ok(t).If(trie.Covers(r3)).Should().Equal(false)
+
+	trie.Insert(r0)
+	prefix, ok := trie.Longest(r3)
+
+	it.Ok(t).
+		If(ok).Should().Equal(true).
+		If(prefix).Should().Equal(r0).
+		If(trie.Covers(r0)).Should().Equal(true)
+}
+
+func aclOf(n int) []iri.ID {
+	acl := make([]iri.ID, n)
+	for i := range acl {
+		acl[i] = iri.New("tenant:%d:orders", i)
+	}
+	return acl
+}
+
+func BenchmarkPrefixTrie(b *testing.B) {
+	var trie iri.PrefixTrie
+	for _, x := range aclOf(5000) {
+		trie.Insert(x)
+	}
+	id := iri.New("tenant:4999:orders:42")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.Covers(id)
+	}
+}
+
+func BenchmarkPrefixScan(b *testing.B) {
+	acl := aclOf(5000)
+	id := iri.New("tenant:4999:orders:42")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, x := range acl {
+			if id.HasPrefix(x) {
+				break
+			}
+		}
+	}
+}
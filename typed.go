@@ -0,0 +1,29 @@
+package iri
+
+/*
+
+Typed is type-safe identity, the type parameter tags the kind of thing.
+Identities of distinct kinds are distinct types, they cannot be mixed
+by mistake. The wire format is same as ID.
+
+  type User = iri.Typed[struct{ user bool }]
+*/
+type Typed[T any] struct {
+	ID
+}
+
+/*
+
+NewTyped parses a compact IRI string into type-safe identity
+*/
+func NewTyped[T any](iri string, args ...interface{}) Typed[T] {
+	return Typed[T]{ID: New(iri, args...)}
+}
+
+/*
+
+Eq return true if identities equals, only identities of same kind are comparable
+*/
+func (iri Typed[T]) Eq(x Typed[T]) bool {
+	return iri.ID.Eq(x.ID)
+}
@@ -0,0 +1,78 @@
+package iri_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+type (
+	user  struct{}
+	order struct{}
+)
+
+func TestTyped(t *testing.T) {
+	a := iri.NewTyped[user]("a:b")
+	b := iri.NewTyped[order]("a:b")
+
+	it.Ok(t).
+		If(a.ID).Should().Equal(r2).
+		If(b.ID).Should().Equal(r2).
+		If(a.Eq(iri.NewTyped[user]("a:b"))).Should().Equal(true).
+		If(a.Eq(iri.NewTyped[user]("a:b:c"))).Should().Equal(false).
+		If(reflect.TypeOf(a) == reflect.TypeOf(b)).Should().Equal(false)
+}
+
+func TestTypedJSON(t *testing.T) {
+	type Struct struct {
+		iri.Typed[user]
+		Title string `json:"title"`
+	}
+	type Plain struct {
+		iri.ID
+		Title string `json:"title"`
+	}
+
+	eg := Struct{Typed: iri.NewTyped[user]("a:b"), Title: "t"}
+	in := Struct{}
+
+	typed, err1 := json.Marshal(eg)
+	plain, err2 := json.Marshal(Plain{ID: r2, Title: "t"})
+	err3 := json.Unmarshal(typed, &in)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(err3).Should().Equal(nil).
+		If(string(typed)).Should().Equal(string(plain)).
+		If(in).Should().Equal(eg)
+}
+
+func TestTypedDynamo(t *testing.T) {
+	type Struct struct {
+		iri.Typed[user]
+		Title string `dynamodbav:"title"`
+	}
+	type Plain struct {
+		iri.ID
+		Title string `dynamodbav:"title"`
+	}
+
+	eg := Struct{Typed: iri.NewTyped[user]("a:b"), Title: "t"}
+	in := Struct{}
+
+	typed, err1 := dynamodbattribute.MarshalMap(eg)
+	plain, err2 := dynamodbattribute.MarshalMap(Plain{ID: r2, Title: "t"})
+	err3 := dynamodbattribute.UnmarshalMap(typed, &in)
+
+	it.Ok(t).
+		If(err1).Should().Equal(nil).
+		If(err2).Should().Equal(nil).
+		If(err3).Should().Equal(nil).
+		If(typed).Should().Equal(plain).
+		If(in).Should().Equal(eg)
+}
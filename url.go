@@ -0,0 +1,123 @@
+package iri
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+/*
+
+URL converts IRI to URL, the path of URL is IRI segments joined by "/"
+*/
+func (iri ID) URL(scheme, host string) *url.URL {
+	return &url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   "/" + iri.Path(),
+	}
+}
+
+/*
+
+FromURL builds IRI from path of URL, leading and trailing "/" are ignored
+*/
+func FromURL(u *url.URL) ID {
+	return ID{IRI: splitPath(u.Path)}
+}
+
+/*
+
+FromPath builds IRI from file system path, it is inverse of Path. Both "/"
+and "\\" are separators, leading and trailing separators are ignored.
+
+  iri.FromPath("/a/b/") ⟼ a:b
+  iri.FromPath(`a\b`)  ⟼ a:b
+*/
+func FromPath(p string) ID {
+	p = strings.ReplaceAll(filepath.ToSlash(p), `\`, "/")
+	return ID{IRI: splitPath(p)}
+}
+
+/*
+
+URLEncode converts IRI to a single URL-safe token, suitable for both path
+segment and query string. All bytes of compact IRI except unreserved
+characters (RFC 3986) are percent-encoded, including ":" separator.
+
+  iri.Join("a", "b c", "ü").URLEncode() ⟼ a%3Ab%20c%3A%C3%BC
+*/
+func (iri ID) URLEncode() string {
+	const hex = "0123456789ABCDEF"
+
+	s := iri.IRI.String()
+	var sb strings.Builder
+	sb.Grow(len(s) * 3)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUnreserved(c) {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[c>>4])
+		sb.WriteByte(hex[c&0x0f])
+	}
+
+	return sb.String()
+}
+
+/*
+
+URLDecode builds IRI from token produced by URLEncode
+*/
+func URLDecode(s string) (ID, error) {
+	val, err := url.PathUnescape(s)
+	if err != nil {
+		return ID{}, fmt.Errorf("iri: invalid url token %q: %w", s, err)
+	}
+
+	return ID{IRI: parse(val)}, nil
+}
+
+/*
+
+WithFragment returns full IRI string with the fragment "prefix:suffix#frag",
+the fragment is not a segment of IRI. The "#" within segments is escaped as
+"%23", it is decoded back by SplitFragment.
+
+  iri.Join("a#b").WithFragment("c") ⟼ a%23b#c
+*/
+func (iri ID) WithFragment(frag string) string {
+	return strings.ReplaceAll(iri.IRI.String(), "#", "%23") + "#" + frag
+}
+
+/*
+
+SplitFragment parses full IRI string into IRI and fragment, the fragment
+starts after the first "#" as per RFC 3987. The fragment is empty if
+string has none. The "%23" within segments is decoded as "#".
+
+  iri.SplitFragment("a:b#c") ⟼ a:b, "c"
+*/
+func SplitFragment(s string) (ID, string) {
+	base, frag, _ := strings.Cut(s, "#")
+	return ID{IRI: parse(base)}, frag
+}
+
+// isUnreserved checks RFC 3986 unreserved characters
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// splitPath builds IRI from "/" delimited path, ignoring leading and trailing "/"
+func splitPath(path string) IRI {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return IRI{Seq: []string{""}}
+	}
+
+	return IRI{Seq: strings.Split(path, "/")}
+}
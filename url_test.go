@@ -0,0 +1,135 @@
+package iri_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/it"
+)
+
+func TestURL(t *testing.T) {
+	it.Ok(t).
+		If(r0.URL("https", "ex.com").String()).Should().Equal("https://ex.com/").
+		If(r3.URL("https", "ex.com").String()).Should().Equal("https://ex.com/a/b/c")
+}
+
+func TestFromURL(t *testing.T) {
+	test := map[string]iri.ID{
+		"https://ex.com":        r0,
+		"https://ex.com/":       r0,
+		"https://ex.com/a":      r1,
+		"https://ex.com/a/b":    r2,
+		"https://ex.com/a/b/":   r2,
+		"https://ex.com/a/b/c/": r3,
+	}
+
+	for eg, expect := range test {
+		u, err := url.Parse(eg)
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(iri.FromURL(u)).Should().Equal(expect)
+	}
+}
+
+func TestURLRoundTrip(t *testing.T) {
+	for _, eg := range []iri.ID{r0, r1, r2, r3, r4, r5} {
+		it.Ok(t).
+			If(iri.FromURL(eg.URL("https", "ex.com"))).Should().Equal(eg)
+	}
+}
+
+func TestURLEncode(t *testing.T) {
+	test := []iri.ID{
+		r0, r1, r5,
+		iri.Join("a", "b/c", "d"),
+		iri.Join("a", "12:00"),
+		iri.Join("a b", "c+d", "e&f=g?"),
+		iri.Join("ü", "日本", "100%"),
+	}
+
+	for _, id := range test {
+		token := id.URLEncode()
+		back, err := iri.URLDecode(token)
+
+		it.Ok(t).
+			If(err).Should().Equal(nil).
+			If(back).Should().Equal(id).
+			If(strings.IndexFunc(token, func(r rune) bool { return r > 0x7f })).Should().Equal(-1).
+			If(strings.ContainsAny(token, ":/ &=?+")).Should().Equal(false)
+	}
+
+	it.Ok(t).
+		If(r3.URLEncode()).Should().Equal("a%3Ab%3Ac").
+		If(iri.Join("a", "b c", "ü").URLEncode()).Should().Equal("a%3Ab%20c%3A%C3%BC")
+}
+
+func TestURLDecodeFailure(t *testing.T) {
+	_, err := iri.URLDecode("a%3")
+	it.Ok(t).If(err).ShouldNot().Equal(nil)
+}
+
+func TestFromPath(t *testing.T) {
+	test := map[string]iri.ID{
+		"":          r0,
+		"/":         r0,
+		"a":         r1,
+		"/a/b":      r2,
+		"a/b/":      r2,
+		"/a/b/c/":   r3,
+		`a\b\c`:     r3,
+		`C:\a\b`:    iri.Join("C:", "a", "b"),
+		`\a\b\c\d\`: r4,
+	}
+
+	for eg, expect := range test {
+		it.Ok(t).If(iri.FromPath(eg)).Should().Equal(expect)
+	}
+
+	for _, id := range []iri.ID{r0, r1, r2, r5} {
+		it.Ok(t).If(iri.FromPath(id.Path())).Should().Equal(id)
+	}
+}
+
+func TestFragment(t *testing.T) {
+	test := map[string]iri.ID{
+		"":     r0,
+		"c":    r3,
+		"x/y?": r2,
+		"12:0": iri.Join("a", "12:00"),
+		"#d":   iri.Join("a#b", "c#"),
+		"%23":  iri.Join("a", "%23"),
+	}
+
+	for frag, id := range test {
+		s := id.WithFragment(frag)
+		base, back := iri.SplitFragment(s)
+
+		it.Ok(t).
+			If(base).Should().Equal(id).
+			If(back).Should().Equal(frag)
+	}
+
+	base, frag := iri.SplitFragment("a:b:c")
+	it.Ok(t).
+		If(r3.WithFragment("x")).Should().Equal("a:b:c#x").
+		If(iri.Join("a#b").WithFragment("f")).Should().Equal("a%23b#f").
+		If(base).Should().Equal(r3).
+		If(frag).Should().Equal("")
+}
+
+func FuzzFragment(f *testing.F) {
+	for _, seed := range [][3]string{{"a", "b", "c"}, {"a#b", "%23", "#d"}, {"%", "12:00", ""}} {
+		f.Add(seed[0], seed[1], seed[2])
+	}
+
+	f.Fuzz(func(t *testing.T, a, b, frag string) {
+		id := iri.Join(a, b)
+		base, back := iri.SplitFragment(id.WithFragment(frag))
+
+		it.Ok(t).
+			If(base).Should().Equal(id).
+			If(back).Should().Equal(frag)
+	})
+}
@@ -0,0 +1,79 @@
+/*
+
+Package validate declares IRI constraints as struct tags of
+github.com/go-playground/validator, so that handlers check incoming
+identities without hand-written code.
+
+  type Request struct {
+		ID iri.ID `validate:"iri,prefix=tenant,maxdepth=4"`
+	}
+
+  v := validator.New()
+  validate.RegisterValidator(v)
+  v.Struct(&Request{ID: iri.New("tenant:acme:order:1")})
+
+Tags:
+
+  iri          ⟼ IRI is not empty and well-formed, see iri.Parse
+  prefix=a:b   ⟼ IRI has the prefix, see iri.ID.HasPrefix
+  maxdepth=n   ⟼ IRI has at most n segments, invalid n fails validation
+*/
+package validate
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/fogfish/iri"
+	"github.com/go-playground/validator/v10"
+)
+
+/*
+
+RegisterValidator registers IRI tags and types at validator instance.
+The iri.ID and iri.IRI fields are validated by its compact form.
+*/
+func RegisterValidator(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(compact, iri.ID{}, iri.IRI{})
+
+	for tag, fn := range map[string]validator.Func{
+		"iri":      isIRI,
+		"prefix":   hasPrefix,
+		"maxdepth": maxDepth,
+	} {
+		// validator fails on empty or reserved tags only, the tags are constant
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// compact form of IRI is the value validated by tags
+func compact(field reflect.Value) interface{} {
+	switch id := field.Interface().(type) {
+	case iri.ID:
+		return id.String()
+	case iri.IRI:
+		return id.String()
+	}
+
+	return nil
+}
+
+func isIRI(fl validator.FieldLevel) bool {
+	id, err := iri.Parse(fl.Field().String())
+	return err == nil && !id.IsEmpty()
+}
+
+func hasPrefix(fl validator.FieldLevel) bool {
+	return iri.FromProto(fl.Field().String()).HasPrefix(iri.FromProto(fl.Param()))
+}
+
+func maxDepth(fl validator.FieldLevel) bool {
+	n, err := strconv.Atoi(fl.Param())
+	if err != nil || n < 0 {
+		return false
+	}
+
+	return iri.FromProto(fl.Field().String()).Len() <= n
+}
@@ -0,0 +1,67 @@
+package validate_test
+
+import (
+	"testing"
+
+	"github.com/fogfish/iri"
+	"github.com/fogfish/iri/validate"
+	"github.com/fogfish/it"
+	"github.com/go-playground/validator/v10"
+)
+
+func TestRegisterValidator(t *testing.T) {
+	v := validator.New()
+	validate.RegisterValidator(v)
+
+	type IRIOnly struct {
+		ID iri.ID `validate:"iri"`
+	}
+
+	type Prefixed struct {
+		ID iri.ID `validate:"prefix=tenant"`
+	}
+
+	type Depth struct {
+		ID iri.ID `validate:"maxdepth=2"`
+	}
+
+	type BadDepth struct {
+		ID iri.ID `validate:"maxdepth=x"`
+	}
+
+	type Request struct {
+		ID  iri.ID  `validate:"iri,prefix=tenant:acme,maxdepth=4"`
+		Ref iri.IRI `validate:"iri"`
+	}
+
+	pass := []interface{}{
+		IRIOnly{ID: iri.New("a:b")},
+		Prefixed{ID: iri.New("tenant:acme")},
+		Prefixed{ID: iri.New("tenant")},
+		Depth{ID: iri.New("a:b")},
+		Depth{ID: iri.New("")},
+		Request{ID: iri.New("tenant:acme:order:1"), Ref: iri.NewIRI("a")},
+	}
+
+	for _, eg := range pass {
+		it.Ok(t).If(v.Struct(eg)).Should().Equal(nil)
+	}
+
+	fail := []interface{}{
+		IRIOnly{ID: iri.New("")},
+		IRIOnly{ID: iri.New("a::b")},
+		IRIOnly{ID: iri.New(" :b")},
+		Prefixed{ID: iri.New("user:acme")},
+		Prefixed{ID: iri.New("tenantx:acme")},
+		Prefixed{ID: iri.New("")},
+		Depth{ID: iri.New("a:b:c")},
+		BadDepth{ID: iri.New("a")},
+		Request{ID: iri.New("tenant:acme:order:1:x"), Ref: iri.NewIRI("a")},
+		Request{ID: iri.New("tenant:globex:order"), Ref: iri.NewIRI("a")},
+		Request{ID: iri.New("tenant:acme:order:1")},
+	}
+
+	for _, eg := range fail {
+		it.Ok(t).If(v.Struct(eg)).ShouldNot().Equal(nil)
+	}
+}